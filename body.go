@@ -1,10 +1,16 @@
 package vital
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math"
+	"mime"
 	"net/http"
 	"reflect"
 	"strconv"
@@ -13,11 +19,105 @@ import (
 
 const defaultMaxBodySize = 1024 * 1024 // 1MB
 
+// defaultSchemalessMaxDepth is the nesting limit applied to map and interface targets
+// when WithMaxDepth is not set.
+const defaultSchemalessMaxDepth = 32
+
+var (
+	// ErrExpectedJSONObject is returned when a JSON body is an array or scalar but the target is an object.
+	ErrExpectedJSONObject = errors.New("expected JSON object")
+	// ErrUnsupportedMediaType is returned when the request Content-Type does not match the decoder.
+	// It maps to 415 Unsupported Media Type.
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	// ErrRequestCanceled is returned when the request context is done while the body is being read.
+	// It maps to 499 Client Closed Request.
+	ErrRequestCanceled = errors.New("request canceled")
+)
+
+// StatusClientClosedRequest is the non-standard status code for requests the client abandoned.
+const StatusClientClosedRequest = 499
+
+// MissingFieldsError is returned when fields tagged required:"true" have zero values.
+type MissingFieldsError struct {
+	// Fields lists the names of the missing fields.
+	Fields []string
+}
+
+// Error implements the error interface.
+func (e *MissingFieldsError) Error() string {
+	return "missing required fields: " + strings.Join(e.Fields, ", ")
+}
+
+// MaxBodySizeError is returned when a request body exceeds the configured size limit.
+type MaxBodySizeError struct {
+	// Limit is the maximum body size in bytes.
+	Limit int64
+}
+
+// Error implements the error interface.
+func (e *MaxBodySizeError) Error() string {
+	return fmt.Sprintf("request body exceeds maximum size of %d bytes", e.Limit)
+}
+
+// MaxDepthError is returned when a JSON body is nested deeper than the configured limit.
+type MaxDepthError struct {
+	// Limit is the maximum nesting depth of objects and arrays.
+	Limit int
+}
+
+// Error implements the error interface.
+func (e *MaxDepthError) Error() string {
+	return fmt.Sprintf("JSON nesting exceeds maximum depth of %d", e.Limit)
+}
+
+// ProblemFromDecodeError converts an error returned by DecodeJSON or DecodeForm into a ProblemDetail:
+//   - MissingFieldsError: 422 with an "errors" extension listing each missing field
+//   - MaxBodySizeError: 413
+//   - ErrUnsupportedMediaType: 415
+//   - ErrRequestCanceled: 499
+//   - anything else: 400
+//
+// Returns nil if err is nil.
+func ProblemFromDecodeError(err error) *ProblemDetail {
+	if err == nil {
+		return nil
+	}
+
+	var missingErr *MissingFieldsError
+	if errors.As(err, &missingErr) {
+		fieldErrors := make([]map[string]string, 0, len(missingErr.Fields))
+		for _, field := range missingErr.Fields {
+			fieldErrors = append(fieldErrors, map[string]string{"field": field, "reason": "required"})
+		}
+
+		return UnprocessableEntity(err.Error()).WithExtension("errors", fieldErrors)
+	}
+
+	var sizeErr *MaxBodySizeError
+
+	switch {
+	case errors.As(err, &sizeErr):
+		return NewProblemDetail(http.StatusRequestEntityTooLarge, "Content Too Large").WithDetail(err.Error())
+	case errors.Is(err, ErrUnsupportedMediaType):
+		return NewProblemDetail(http.StatusUnsupportedMediaType, "Unsupported Media Type").WithDetail(err.Error())
+	case errors.Is(err, ErrRequestCanceled):
+		return NewProblemDetail(StatusClientClosedRequest, "Client Closed Request").WithDetail(err.Error())
+	default:
+		return BadRequest(err.Error())
+	}
+}
+
 // DecodeOption configures body decoding behavior.
 type DecodeOption func(*decodeConfig)
 
 type decodeConfig struct {
-	maxBodySize int64
+	maxBodySize            int64
+	maxRecordSize          int64
+	maxDepth               int
+	requireJSONContentType bool
+	useNumber              bool
+	emptyAsZero            bool
+	writer                 http.ResponseWriter
 }
 
 // WithMaxBodySize sets a custom body size limit.
@@ -27,7 +127,141 @@ func WithMaxBodySize(size int64) DecodeOption {
 	}
 }
 
+// WithMaxRecordSize sets the size limit of a single record for DecodeJSONSeq
+// (default 1MB). The overall stream is still limited by WithMaxBodySize.
+func WithMaxRecordSize(size int64) DecodeOption {
+	return func(c *decodeConfig) {
+		c.maxRecordSize = size
+	}
+}
+
+// WithMaxDepth rejects JSON bodies whose objects and arrays are nested deeper than n
+// with a MaxDepthError. The body is scanned before it is unmarshaled into the target,
+// which guards against deeply nested payloads. Applies to DecodeJSON and DecodeJSONSeq.
+// Schemaless targets such as map[string]any are limited to a depth of 32 by default.
+func WithMaxDepth(n int) DecodeOption {
+	return func(c *decodeConfig) {
+		c.maxDepth = n
+	}
+}
+
+// WithUseNumber decodes JSON numbers into json.Number instead of float64 when the target is
+// an interface, such as the values of map[string]any, so large integers keep their precision.
+func WithUseNumber() DecodeOption {
+	return func(c *decodeConfig) {
+		c.useNumber = true
+	}
+}
+
+// WithEmptyAsZero makes DecodeForm treat an empty value for a numeric or boolean field as
+// not provided, leaving the zero value instead of failing to parse it. This matches HTML forms,
+// which submit empty inputs as empty strings. Required fields left empty are still reported.
+func WithEmptyAsZero() DecodeOption {
+	return func(c *decodeConfig) {
+		c.emptyAsZero = true
+	}
+}
+
+// WithResponseWriter passes the handler's ResponseWriter to http.MaxBytesReader, so the server
+// closes the connection after the response when a body exceeds the size limit instead of
+// trying to drain the rest of it. It applies to DecodeJSON, DecodeJSONSeq, DecodeForm, and
+// ReadBody.
+func WithResponseWriter(w http.ResponseWriter) DecodeOption {
+	return func(c *decodeConfig) {
+		c.writer = w
+	}
+}
+
+// WithRequireJSONContentType rejects requests whose Content-Type is not application/json
+// (parameters such as charset are ignored) with ErrUnsupportedMediaType before decoding.
+func WithRequireJSONContentType() DecodeOption {
+	return func(c *decodeConfig) {
+		c.requireJSONContentType = true
+	}
+}
+
+// RequireBody returns a middleware that rejects POST, PUT, and PATCH requests without a body
+// with a 400 Bad Request ProblemDetail. When the content length is unknown, the body is peeked
+// through a buffered reader so the handler still receives it unread.
+func RequireBody() Middleware {
+	return func(next http.Handler) http.Handler {
+		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !requiresBody(r.Method) || r.ContentLength > 0 {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			if r.Body == nil || r.Body == http.NoBody {
+				RespondProblem(w, BadRequest("request body is required"))
+
+				return
+			}
+
+			buffered := bufio.NewReader(r.Body)
+
+			_, err := buffered.Peek(1)
+			if err != nil {
+				RespondProblem(w, BadRequest("request body is required"))
+
+				return
+			}
+
+			r.Body = &bufferedBody{Reader: buffered, Closer: r.Body}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// LimitContentLength returns a middleware that rejects requests whose declared Content-Length
+// exceeds maxBytes with a 413 ProblemDetail before the body is read. Because http.Server only
+// sends "100 Continue" once the handler reads the body, clients sending "Expect: 100-continue"
+// receive the rejection without uploading the body. Bodies of unknown length are capped with
+// http.MaxBytesReader, so reads beyond maxBytes fail.
+func LimitContentLength(maxBytes int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				problem := ProblemFromDecodeError(&MaxBodySizeError{Limit: maxBytes})
+
+				// The connection cannot be reused if the client already started sending the body
+				w.Header().Set("Connection", "close")
+				RespondProblem(w, problem)
+
+				return
+			}
+
+			if r.Body != nil && r.Body != http.NoBody {
+				r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requiresBody reports whether requests with the given method must carry a body.
+func requiresBody(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return true
+	default:
+		return false
+	}
+}
+
+// bufferedBody replaces a request body with a buffered reader while keeping the original closer.
+type bufferedBody struct {
+	io.Reader
+	io.Closer
+}
+
 // DecodeJSON decodes a JSON request body into type T with validation.
+// Schemaless targets such as map[string]any are supported: required-field validation only
+// applies to structs, and their nesting depth is limited (see WithMaxDepth).
 func DecodeJSON[T any](r *http.Request, opts ...DecodeOption) (T, error) {
 	var zero T
 
@@ -35,32 +269,78 @@ func DecodeJSON[T any](r *http.Request, opts ...DecodeOption) (T, error) {
 		maxBodySize: defaultMaxBodySize,
 	}
 
+	if isSchemaless(reflect.TypeFor[T]()) {
+		config.maxDepth = defaultSchemalessMaxDepth
+	}
+
 	for _, opt := range opts {
 		opt(&config)
 	}
 
-	limitedReader := io.LimitReader(r.Body, config.maxBodySize+1)
+	if config.requireJSONContentType {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mediaType != "application/json" {
+			return zero, fmt.Errorf("%w: expected application/json, got %q", ErrUnsupportedMediaType, mediaType)
+		}
+	}
+
+	body := io.NopCloser(&contextReader{ctx: r.Context(), reader: r.Body})
+	limitedReader := bufio.NewReader(http.MaxBytesReader(config.writer, body, config.maxBodySize))
+
+	if config.maxDepth > 0 {
+		data, err := io.ReadAll(limitedReader)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return zero, &MaxBodySizeError{Limit: config.maxBodySize}
+			}
+
+			return zero, err
+		}
+
+		if exceedsJSONDepth(data, config.maxDepth) {
+			return zero, &MaxDepthError{Limit: config.maxDepth}
+		}
+
+		limitedReader = bufio.NewReader(bytes.NewReader(data))
+	}
+
+	if expectsJSONObject(reflect.TypeFor[T]()) {
+		if kind := peekJSONKind(limitedReader); kind != "" && kind != "object" && kind != "null" {
+			return zero, fmt.Errorf("%w, got %s", ErrExpectedJSONObject, kind)
+		}
+	}
+
 	decoder := json.NewDecoder(limitedReader)
+	if config.useNumber {
+		decoder.UseNumber()
+	}
 
 	var result T
 	if err := decoder.Decode(&result); err != nil {
-		if errors.Is(err, io.EOF) {
-			return zero, fmt.Errorf("empty request body")
+		if errors.Is(err, ErrRequestCanceled) {
+			return zero, err
 		}
 
-		if decoder.More() {
-			var buf [1]byte
-			if _, readErr := limitedReader.Read(buf[:]); readErr == nil {
-				return zero, fmt.Errorf("request body exceeds maximum size of %d bytes", config.maxBodySize)
-			}
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return zero, &MaxBodySizeError{Limit: config.maxBodySize}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return zero, fmt.Errorf("empty request body")
 		}
 
 		return zero, fmt.Errorf("invalid JSON: %w", err)
 	}
 
-	var buf [1]byte
-	if n, _ := limitedReader.Read(buf[:]); n > 0 {
-		return zero, fmt.Errorf("request body exceeds maximum size of %d bytes", config.maxBodySize)
+	var (
+		buf         [1]byte
+		maxBytesErr *http.MaxBytesError
+	)
+
+	if n, readErr := limitedReader.Read(buf[:]); n > 0 || errors.As(readErr, &maxBytesErr) {
+		return zero, &MaxBodySizeError{Limit: config.maxBodySize}
 	}
 
 	if err := validateRequired(result); err != nil {
@@ -70,6 +350,356 @@ func DecodeJSON[T any](r *http.Request, opts ...DecodeOption) (T, error) {
 	return result, nil
 }
 
+// ReadBody reads the raw request body, for example to verify a webhook signature, enforcing
+// the same size limit as the decoders (WithMaxBodySize, default 1MB). A body exceeding the
+// limit returns a MaxBodySizeError. The body is fully consumed.
+func ReadBody(r *http.Request, opts ...DecodeOption) ([]byte, error) {
+	config := decodeConfig{
+		maxBodySize: defaultMaxBodySize,
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return []byte{}, nil
+	}
+
+	body := io.NopCloser(&contextReader{ctx: r.Context(), reader: r.Body})
+
+	data, err := io.ReadAll(http.MaxBytesReader(config.writer, body, config.maxBodySize))
+	if err != nil {
+		if errors.Is(err, ErrRequestCanceled) {
+			return nil, err
+		}
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, &MaxBodySizeError{Limit: config.maxBodySize}
+		}
+
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	return data, nil
+}
+
+// DecodeJSONOrRespond decodes a JSON request body like DecodeJSON. On error it writes the
+// ProblemDetail from ProblemFromDecodeError and returns false, so handlers can simply return:
+//
+//	req, ok := vital.DecodeJSONOrRespond[CreateUserRequest](w, r)
+//	if !ok {
+//	    return
+//	}
+func DecodeJSONOrRespond[T any](w http.ResponseWriter, r *http.Request, opts ...DecodeOption) (T, bool) {
+	result, err := DecodeJSON[T](r, opts...)
+	if err != nil {
+		RespondProblem(w, ProblemFromDecodeError(err))
+
+		return result, false
+	}
+
+	return result, true
+}
+
+// recordSeparator is the ASCII record separator that prefixes each record in RFC 7464 JSON text sequences.
+const recordSeparator = 0x1E
+
+// DecodeJSONSeq decodes a stream of JSON records from the request body, one at a time,
+// and calls fn for each record in order. Records may be separated by RS (application/json-seq,
+// RFC 7464) or newlines (NDJSON). Each record is validated like DecodeJSON; decoding stops at the
+// first decode, validation, or callback error, which is returned annotated with the 1-based
+// record number. Single records are limited by WithMaxRecordSize and the whole stream by
+// WithMaxBodySize; both limits produce a MaxBodySizeError. When reading the body fails, the
+// records completed before the failure are passed to fn before the read error is returned.
+//
+// With WithRequireJSONContentType, the Content-Type must be application/json-seq or application/x-ndjson.
+func DecodeJSONSeq[T any](r *http.Request, fn func(T) error, opts ...DecodeOption) error {
+	config := decodeConfig{
+		maxBodySize:   defaultMaxBodySize,
+		maxRecordSize: defaultMaxBodySize,
+	}
+
+	if isSchemaless(reflect.TypeFor[T]()) {
+		config.maxDepth = defaultSchemalessMaxDepth
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if config.requireJSONContentType {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mediaType != "application/json-seq" && mediaType != "application/x-ndjson" {
+			return fmt.Errorf("%w: expected application/json-seq or application/x-ndjson, got %q",
+				ErrUnsupportedMediaType, mediaType)
+		}
+	}
+
+	body := io.NopCloser(&contextReader{ctx: r.Context(), reader: r.Body})
+
+	limited := &errorRecordingReader{reader: http.MaxBytesReader(config.writer, body, config.maxBodySize)}
+
+	// Clamp the record limit so the scanner's buffer size of one more byte fits in an int
+	recordLimit := int(min(config.maxRecordSize, int64(math.MaxInt-1)))
+
+	var terminated bool
+
+	scanner := bufio.NewScanner(limited)
+	scanner.Buffer(make([]byte, 0, min(recordLimit+1, bufio.MaxScanTokenSize)), recordLimit+1)
+	scanner.Split(jsonRecordSplitter(&terminated))
+
+	record := 0
+
+	for scanner.Scan() {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		record++
+
+		// When reading fails, records terminated before the failure are still decoded, but the
+		// unterminated final record may be truncated
+		if !terminated && limited.err != nil && !errors.Is(limited.err, io.EOF) {
+			break
+		}
+
+		if int64(len(data)) > config.maxRecordSize {
+			return fmt.Errorf("record %d: %w", record, &MaxBodySizeError{Limit: config.maxRecordSize})
+		}
+
+		if config.maxDepth > 0 && exceedsJSONDepth(data, config.maxDepth) {
+			return fmt.Errorf("record %d: %w", record, &MaxDepthError{Limit: config.maxDepth})
+		}
+
+		var result T
+
+		err := unmarshalJSON(data, &result, config.useNumber)
+		if err != nil {
+			return fmt.Errorf("record %d: invalid JSON: %w", record, err)
+		}
+
+		err = validateRequired(result)
+		if err != nil {
+			return fmt.Errorf("record %d: %w", record, err)
+		}
+
+		err = fn(result)
+		if err != nil {
+			return fmt.Errorf("record %d: %w", record, err)
+		}
+	}
+
+	err := scanner.Err()
+	if err == nil {
+		err = limited.err
+	}
+
+	if err == nil || errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.Is(err, ErrRequestCanceled):
+		return err
+	case errors.As(err, &maxBytesErr):
+		return &MaxBodySizeError{Limit: config.maxBodySize}
+	case errors.Is(err, bufio.ErrTooLong):
+		return fmt.Errorf("record %d: %w", record+1, &MaxBodySizeError{Limit: config.maxRecordSize})
+	default:
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+}
+
+// errorRecordingReader remembers the last error returned by the underlying reader.
+type errorRecordingReader struct {
+	reader io.Reader
+	err    error
+}
+
+// Read reads from the underlying reader and records any error.
+func (r *errorRecordingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if err != nil {
+		r.err = err
+	}
+
+	return n, err
+}
+
+// jsonRecordSplitter returns a bufio.SplitFunc for JSON records. A stream starting with a
+// record separator is split on record separators only, so records may span lines;
+// any other stream is split on newlines. terminated reports whether the last record ended
+// with a separator rather than at the end of the input.
+func jsonRecordSplitter(terminated *bool) bufio.SplitFunc {
+	var separator byte
+
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if separator == 0 {
+			trimmed := bytes.TrimLeft(data, " \t\r\n")
+			if len(trimmed) == 0 && !atEOF {
+				return 0, nil, nil
+			}
+
+			separator = '\n'
+			if len(trimmed) > 0 && trimmed[0] == recordSeparator {
+				separator = recordSeparator
+			}
+		}
+
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+
+		if i := bytes.IndexByte(data, separator); i >= 0 {
+			*terminated = true
+
+			return i + 1, data[:i], nil
+		}
+
+		if atEOF {
+			*terminated = false
+
+			return len(data), data, nil
+		}
+
+		return 0, nil, nil
+	}
+}
+
+// contextReader aborts reads once its context is done, so a cancelled request
+// stops consuming the body at the next read instead of reading to EOF.
+type contextReader struct {
+	ctx    context.Context //nolint:containedctx // Reader is scoped to a single request body
+	reader io.Reader
+}
+
+// Read reads from the underlying reader unless the context is done.
+func (r *contextReader) Read(p []byte) (int, error) {
+	err := r.ctx.Err()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrRequestCanceled, err)
+	}
+
+	return r.reader.Read(p)
+}
+
+// exceedsJSONDepth reports whether objects and arrays in data are nested deeper than limit.
+// Brackets inside strings are ignored; syntax errors are left to the decoder.
+func exceedsJSONDepth(data []byte, limit int) bool {
+	depth := 0
+	inString := false
+	escaped := false
+
+	for _, char := range data {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			switch char {
+			case '\\':
+				escaped = true
+			case '"':
+				inString = false
+			}
+		case char == '"':
+			inString = true
+		case char == '{' || char == '[':
+			depth++
+			if depth > limit {
+				return true
+			}
+		case char == '}' || char == ']':
+			depth--
+		}
+	}
+
+	return false
+}
+
+// isSchemaless reports whether the type holds arbitrary JSON, such as map[string]any or any.
+func isSchemaless(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	return typ.Kind() == reflect.Interface ||
+		(typ.Kind() == reflect.Map && typ.Elem().Kind() == reflect.Interface)
+}
+
+// unmarshalJSON unmarshals data into target, optionally decoding numbers as json.Number.
+func unmarshalJSON(data []byte, target any, useNumber bool) error {
+	if !useNumber {
+		return json.Unmarshal(data, target) //nolint:wrapcheck // Callers add context
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	return decoder.Decode(target) //nolint:wrapcheck // Callers add context
+}
+
+// expectsJSONObject reports whether the type decodes from a JSON object. Types implementing
+// json.Unmarshaler or encoding.TextUnmarshaler decode themselves from any JSON value, such as
+// time.Time from a string, and are not restricted.
+func expectsJSONObject(typ reflect.Type) bool {
+	for {
+		if unmarshalsItself(typ) || unmarshalsItself(reflect.PointerTo(typ)) {
+			return false
+		}
+
+		if typ.Kind() != reflect.Pointer {
+			break
+		}
+
+		typ = typ.Elem()
+	}
+
+	return typ.Kind() == reflect.Struct || typ.Kind() == reflect.Map
+}
+
+// unmarshalsItself reports whether the type implements json.Unmarshaler or encoding.TextUnmarshaler.
+func unmarshalsItself(typ reflect.Type) bool {
+	return typ.Implements(reflect.TypeFor[json.Unmarshaler]()) ||
+		typ.Implements(reflect.TypeFor[encoding.TextUnmarshaler]())
+}
+
+// peekJSONKind skips leading whitespace and reports the kind of the next JSON value
+// without consuming it. Returns an empty string if the kind cannot be determined.
+func peekJSONKind(reader *bufio.Reader) string {
+	for {
+		next, err := reader.Peek(1)
+		if err != nil {
+			return ""
+		}
+
+		switch next[0] {
+		case ' ', '\t', '\n', '\r':
+			_, _ = reader.ReadByte()
+
+			continue
+		case '{':
+			return "object"
+		case '[':
+			return "array"
+		case '"':
+			return "string"
+		case 't', 'f':
+			return "boolean"
+		case 'n':
+			return "null"
+		case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			return "number"
+		default:
+			return ""
+		}
+	}
+}
+
 // DecodeForm decodes a form urlencoded request body into type T with validation.
 func DecodeForm[T any](r *http.Request, opts ...DecodeOption) (T, error) {
 	var zero T
@@ -82,19 +712,19 @@ func DecodeForm[T any](r *http.Request, opts ...DecodeOption) (T, error) {
 		opt(&config)
 	}
 
-	r.Body = http.MaxBytesReader(nil, r.Body, config.maxBodySize)
+	r.Body = http.MaxBytesReader(config.writer, r.Body, config.maxBodySize)
 
 	if err := r.ParseForm(); err != nil {
 		var maxBytesErr *http.MaxBytesError
 		if errors.As(err, &maxBytesErr) {
-			return zero, fmt.Errorf("request body exceeds maximum size of %d bytes", config.maxBodySize)
+			return zero, &MaxBodySizeError{Limit: config.maxBodySize}
 		}
 
 		return zero, fmt.Errorf("invalid form data: %w", err)
 	}
 
 	var result T
-	if err := decodeFormToStruct(r.Form, &result); err != nil {
+	if err := decodeFormToStruct(r.Form, &result, config.emptyAsZero); err != nil {
 		return zero, err
 	}
 
@@ -105,7 +735,7 @@ func DecodeForm[T any](r *http.Request, opts ...DecodeOption) (T, error) {
 	return result, nil
 }
 
-func decodeFormToStruct(form map[string][]string, target any) error {
+func decodeFormToStruct(form map[string][]string, target any, emptyAsZero bool) error {
 	val := reflect.ValueOf(target).Elem()
 	typ := val.Type()
 
@@ -128,6 +758,9 @@ func decodeFormToStruct(form map[string][]string, target any) error {
 		}
 
 		formValue := formValues[0]
+		if formValue == "" && emptyAsZero && field.Kind() != reflect.String {
+			continue
+		}
 
 		switch field.Kind() {
 		case reflect.String:
@@ -162,8 +795,18 @@ func decodeFormToStruct(form map[string][]string, target any) error {
 	return nil
 }
 
+// validateRequired checks fields tagged required:"true". Non-struct values, such as maps
+// decoded from schemaless bodies, have no required fields and always pass.
 func validateRequired(v any) error {
 	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Pointer && !val.IsNil() {
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+
 	typ := val.Type()
 
 	var missingFields []string
@@ -184,7 +827,7 @@ func validateRequired(v any) error {
 	}
 
 	if len(missingFields) > 0 {
-		return fmt.Errorf("missing required fields: %s", strings.Join(missingFields, ", "))
+		return &MissingFieldsError{Fields: missingFields}
 	}
 
 	return nil
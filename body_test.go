@@ -1,12 +1,18 @@
 package vital_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/monkescience/vital"
 )
@@ -86,6 +92,204 @@ type testOptionalFields struct {
 	Country string `json:"country" form:"country"`
 }
 
+func TestRequireBody(t *testing.T) {
+	var received string
+
+	handler := vital.RequireBody()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name             string
+		method           string
+		body             io.Reader
+		unknownLength    bool
+		expectedStatus   int
+		expectedReceived string
+	}{
+		{
+			name:           "empty POST",
+			method:         http.MethodPost,
+			body:           nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "empty chunked PUT",
+			method:         http.MethodPut,
+			body:           strings.NewReader(""),
+			unknownLength:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:             "non-empty POST",
+			method:           http.MethodPost,
+			body:             strings.NewReader(`{"name":"Alice"}`),
+			expectedStatus:   http.StatusOK,
+			expectedReceived: `{"name":"Alice"}`,
+		},
+		{
+			name:             "non-empty chunked PATCH",
+			method:           http.MethodPatch,
+			body:             strings.NewReader(`{"name":"Bob"}`),
+			unknownLength:    true,
+			expectedStatus:   http.StatusOK,
+			expectedReceived: `{"name":"Bob"}`,
+		},
+		{
+			name:           "GET without body",
+			method:         http.MethodGet,
+			body:           nil,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a request with or without a body
+			received = ""
+
+			req := httptest.NewRequest(tt.method, "/", tt.body)
+			if tt.unknownLength {
+				req.ContentLength = -1
+			}
+
+			rec := httptest.NewRecorder()
+
+			// WHEN: the handler processes the request
+			handler.ServeHTTP(rec, req)
+
+			// THEN: it should reject empty bodies and pass others intact
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+
+			if received != tt.expectedReceived {
+				t.Errorf("expected handler to receive %q, got %q", tt.expectedReceived, received)
+			}
+
+			if tt.expectedStatus == http.StatusBadRequest &&
+				rec.Header().Get("Content-Type") != "application/problem+json" {
+				t.Errorf("expected problem response, got %q", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+// trackingReader records whether its content was read.
+type trackingReader struct {
+	reader io.Reader
+	read   atomic.Bool
+}
+
+func (r *trackingReader) Read(p []byte) (int, error) {
+	r.read.Store(true)
+
+	return r.reader.Read(p)
+}
+
+func TestLimitContentLength(t *testing.T) {
+	handlerCalled := atomic.Bool{}
+
+	handler := vital.LimitContentLength(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerCalled.Store(true)
+
+		_, err := io.ReadAll(r.Body)
+		if err != nil {
+			vital.RespondProblem(w, vital.NewProblemDetail(http.StatusRequestEntityTooLarge, "Content Too Large"))
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{ExpectContinueTimeout: 5 * time.Second}}
+	defer client.CloseIdleConnections()
+
+	tests := []struct {
+		name           string
+		size           int
+		expectedStatus int
+		expectBodyRead bool
+		expectHandler  bool
+	}{
+		{
+			name:           "over-limit upload is rejected before the body is sent",
+			size:           4096,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectBodyRead: false,
+			expectHandler:  false,
+		},
+		{
+			name:           "within-limit upload continues",
+			size:           512,
+			expectedStatus: http.StatusOK,
+			expectBodyRead: true,
+			expectHandler:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a client announcing its upload with Expect: 100-continue
+			handlerCalled.Store(false)
+
+			body := &trackingReader{reader: strings.NewReader(strings.Repeat("x", tt.size))}
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, body)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+
+			req.ContentLength = int64(tt.size)
+			req.Header.Set("Expect", "100-continue")
+
+			// WHEN: sending the request
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+
+			_ = resp.Body.Close()
+
+			// THEN: oversized uploads are rejected at the 100-continue stage
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+
+			if body.read.Load() != tt.expectBodyRead {
+				t.Errorf("expected body read=%v, got %v", tt.expectBodyRead, body.read.Load())
+			}
+
+			if handlerCalled.Load() != tt.expectHandler {
+				t.Errorf("expected handler called=%v, got %v", tt.expectHandler, handlerCalled.Load())
+			}
+		})
+	}
+
+	t.Run("unknown length is capped", func(t *testing.T) {
+		// GIVEN: a chunked request exceeding the limit
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("x", 2048)))
+		req.ContentLength = -1
+
+		rec := httptest.NewRecorder()
+
+		// WHEN: the handler reads the body
+		handler.ServeHTTP(rec, req)
+
+		// THEN: reading fails at the limit
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("expected status %d, got %d", http.StatusRequestEntityTooLarge, rec.Code)
+		}
+	})
+}
+
 func TestDecodeJSON_ValidJSON(t *testing.T) {
 	// GIVEN: a request with valid JSON body
 	jsonBody := `{"name":"Alice","email":"alice@example.com","age":30}`
@@ -225,6 +429,236 @@ func TestDecodeJSON_UnknownFields(t *testing.T) {
 	}
 }
 
+func TestDecodeJSON_NonObjectBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		jsonBody string
+		expected string
+	}{
+		{
+			name:     "string scalar",
+			jsonBody: `"hello"`,
+			expected: "expected JSON object, got string",
+		},
+		{
+			name:     "number scalar",
+			jsonBody: `  42`,
+			expected: "expected JSON object, got number",
+		},
+		{
+			name:     "array",
+			jsonBody: "\n[{\"name\":\"Alice\"}]",
+			expected: "expected JSON object, got array",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a request with a non-object JSON body
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			// WHEN: decoding into an object target
+			_, err := vital.DecodeJSON[testUser](req)
+
+			// THEN: it should return a descriptive object-expected error
+			if !errors.Is(err, vital.ErrExpectedJSONObject) {
+				t.Fatalf("expected ErrExpectedJSONObject, got %v", err)
+			}
+
+			if err.Error() != tt.expected {
+				t.Errorf("expected error %q, got %q", tt.expected, err.Error())
+			}
+
+			problem := vital.BadRequest(err.Error())
+			if problem.Status != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", problem.Status)
+			}
+		})
+	}
+}
+
+// triple is a struct that unmarshals itself from a JSON array.
+type triple struct {
+	A, B, C int
+}
+
+func (t *triple) UnmarshalJSON(data []byte) error {
+	var values [3]int
+
+	err := json.Unmarshal(data, &values)
+	if err != nil {
+		return err
+	}
+
+	t.A, t.B, t.C = values[0], values[1], values[2]
+
+	return nil
+}
+
+func TestDecodeJSON_SelfUnmarshalingTypes(t *testing.T) {
+	t.Run("time from a string", func(t *testing.T) {
+		// GIVEN: a JSON string body
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`"2024-01-01T00:00:00Z"`))
+
+		// WHEN: decoding into a type implementing encoding.TextUnmarshaler
+		got, err := vital.DecodeJSON[time.Time](req)
+
+		// THEN: the type decodes itself from the string
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if !got.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("expected 2024-01-01, got %v", got)
+		}
+	})
+
+	t.Run("struct from an array", func(t *testing.T) {
+		// GIVEN: a JSON array body
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`[1,2,3]`))
+
+		// WHEN: decoding into a struct implementing json.Unmarshaler
+		got, err := vital.DecodeJSON[triple](req)
+
+		// THEN: the struct decodes itself from the array
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if got != (triple{A: 1, B: 2, C: 3}) {
+			t.Errorf("expected {1 2 3}, got %+v", got)
+		}
+	})
+}
+
+func TestDecodeJSON_RequireJSONContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		expectErr   bool
+	}{
+		{
+			name:        "form body",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "name=Alice&email=alice@example.com",
+			expectErr:   true,
+		},
+		{
+			name:        "missing content type",
+			contentType: "",
+			body:        `{"name":"Alice","email":"alice@example.com"}`,
+			expectErr:   true,
+		},
+		{
+			name:        "json with charset",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"name":"Alice","email":"alice@example.com"}`,
+			expectErr:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a request with the given content type
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			// WHEN: decoding with the content type requirement enabled
+			_, err := vital.DecodeJSON[testUser](req, vital.WithRequireJSONContentType())
+
+			// THEN: it should reject non-JSON content types with a distinct error
+			if tt.expectErr && !errors.Is(err, vital.ErrUnsupportedMediaType) {
+				t.Errorf("expected ErrUnsupportedMediaType, got %v", err)
+			}
+
+			if !tt.expectErr && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDecodeJSON_LenientContentTypeByDefault(t *testing.T) {
+	// GIVEN: a JSON body sent with a text content type
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Alice","email":"alice@example.com"}`))
+	req.Header.Set("Content-Type", "text/plain")
+
+	// WHEN: decoding without the content type requirement
+	_, err := vital.DecodeJSON[testUser](req)
+
+	// THEN: it should decode successfully
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+// failingReader returns its data together with an error, like a connection that breaks.
+type failingReader struct {
+	data []byte
+	err  error
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	n := copy(p, f.data)
+	f.data = f.data[n:]
+
+	return n, f.err
+}
+
+// slowReader yields one byte per interval to simulate a slow upload.
+type slowReader struct {
+	data     []byte
+	interval time.Duration
+}
+
+func (s *slowReader) Read(p []byte) (int, error) {
+	if len(s.data) == 0 {
+		return 0, io.EOF
+	}
+
+	time.Sleep(s.interval)
+
+	p[0] = s.data[0]
+	s.data = s.data[1:]
+
+	return 1, nil
+}
+
+func TestDecodeJSON_ContextCancellation(t *testing.T) {
+	// GIVEN: a slow request body and a context cancelled mid-read
+	body := &slowReader{
+		data:     []byte(`{"name":"` + strings.Repeat("a", 1000) + `","email":"alice@example.com"}`),
+		interval: 5 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodPost, "/", body).WithContext(ctx)
+
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	// WHEN: decoding the body
+	start := time.Now()
+	_, err := vital.DecodeJSON[testUser](req)
+	elapsed := time.Since(start)
+
+	// THEN: it should abort promptly with a cancellation error
+	if !errors.Is(err, vital.ErrRequestCanceled) {
+		t.Fatalf("expected ErrRequestCanceled, got %v", err)
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error to wrap context.Canceled, got %v", err)
+	}
+
+	if elapsed > time.Second {
+		t.Errorf("expected prompt cancellation, took %v", elapsed)
+	}
+}
+
 func TestDecodeJSON_BodySizeLimit(t *testing.T) {
 	// GIVEN: a request with body exceeding 1MB default limit
 	largeBody := strings.Repeat("x", 1024*1024+1) // 1MB + 1 byte
@@ -263,6 +697,362 @@ func TestDecodeJSON_CustomBodySizeLimit(t *testing.T) {
 	}
 }
 
+func TestDecodeJSON_MaxDepth(t *testing.T) {
+	type payload struct {
+		Data any `json:"data"`
+	}
+
+	tests := []struct {
+		name        string
+		body        string
+		expectDepth bool
+	}{
+		{
+			name:        "within limit",
+			body:        `{"data":[[{"a":1}]]}`,
+			expectDepth: false,
+		},
+		{
+			name:        "brackets inside strings are ignored",
+			body:        `{"data":"[[[[[[\"{{{{"}`,
+			expectDepth: false,
+		},
+		{
+			name:        "nested past limit",
+			body:        `{"data":` + strings.Repeat("[", 10) + strings.Repeat("]", 10) + `}`,
+			expectDepth: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a request body and a maximum depth of 4
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+
+			// WHEN: decoding with the depth limit
+			_, err := vital.DecodeJSON[payload](req, vital.WithMaxDepth(4))
+
+			// THEN: only bodies nested past the limit are rejected
+			var depthErr *vital.MaxDepthError
+
+			if tt.expectDepth {
+				if !errors.As(err, &depthErr) {
+					t.Fatalf("expected MaxDepthError, got %v", err)
+				}
+
+				if depthErr.Limit != 4 {
+					t.Errorf("expected limit 4, got %d", depthErr.Limit)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDecodeJSON_Map(t *testing.T) {
+	t.Run("decodes nested object", func(t *testing.T) {
+		// GIVEN: a nested object body with a large integer
+		body := `{"user":{"id":9007199254740993,"tags":["a","b"]},"active":true}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+
+		// WHEN: decoding into map[string]any with json.Number
+		result, err := vital.DecodeJSON[map[string]any](req, vital.WithUseNumber())
+
+		// THEN: the object is decoded without required-field validation
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		user, ok := result["user"].(map[string]any)
+		if !ok {
+			t.Fatalf("expected nested user object, got %T", result["user"])
+		}
+
+		if user["id"] != json.Number("9007199254740993") {
+			t.Errorf("expected id to keep its precision, got %v", user["id"])
+		}
+	})
+
+	t.Run("enforces default depth", func(t *testing.T) {
+		// GIVEN: a body nested deeper than the schemaless default
+		body := `{"a":` + strings.Repeat("[", 40) + strings.Repeat("]", 40) + `}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+
+		// WHEN: decoding into map[string]any
+		_, err := vital.DecodeJSON[map[string]any](req)
+
+		// THEN: a MaxDepthError is returned
+		var depthErr *vital.MaxDepthError
+		if !errors.As(err, &depthErr) {
+			t.Fatalf("expected MaxDepthError, got %v", err)
+		}
+	})
+
+	t.Run("honors explicit depth", func(t *testing.T) {
+		// GIVEN: a body nested three levels deep
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"a":{"b":{"c":1}}}`))
+
+		// WHEN: decoding into map[string]any with a depth limit of 2
+		_, err := vital.DecodeJSON[map[string]any](req, vital.WithMaxDepth(2))
+
+		// THEN: a MaxDepthError with the configured limit is returned
+		var depthErr *vital.MaxDepthError
+		if !errors.As(err, &depthErr) || depthErr.Limit != 2 {
+			t.Fatalf("expected MaxDepthError with limit 2, got %v", err)
+		}
+	})
+}
+
+func TestDecodeJSONSeq(t *testing.T) {
+	t.Run("invokes callback per record", func(t *testing.T) {
+		bodies := map[string]string{
+			"ndjson":   "{\"name\":\"a\",\"email\":\"a@x\"}\n{\"name\":\"b\",\"email\":\"b@x\"}\n\n{\"name\":\"c\",\"email\":\"c@x\"}",
+			"json-seq": "\x1e{\"name\":\"a\",\"email\":\"a@x\"}\n\x1e{\n  \"name\": \"b\",\n  \"email\": \"b@x\"\n}\n\x1e{\"name\":\"c\",\"email\":\"c@x\"}\n",
+		}
+
+		for format, body := range bodies {
+			t.Run(format, func(t *testing.T) {
+				// GIVEN: a request streaming three records
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+
+				var names []string
+
+				// WHEN: decoding the sequence
+				err := vital.DecodeJSONSeq(req, func(user testUser) error {
+					names = append(names, user.Name)
+
+					return nil
+				})
+
+				// THEN: the callback fires once per record in order
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+
+				if strings.Join(names, ",") != "a,b,c" {
+					t.Errorf("expected records a,b,c, got %v", names)
+				}
+			})
+		}
+	})
+
+	t.Run("stops at the first error", func(t *testing.T) {
+		// GIVEN: a stream whose second record misses required fields
+		body := "{\"name\":\"a\",\"email\":\"a@x\"}\n{\"age\":3}\n{\"name\":\"c\",\"email\":\"c@x\"}\n"
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+
+		calls := 0
+
+		// WHEN: decoding the sequence
+		err := vital.DecodeJSONSeq(req, func(testUser) error {
+			calls++
+
+			return nil
+		})
+
+		// THEN: decoding stops with a validation error for record 2
+		var missingErr *vital.MissingFieldsError
+		if !errors.As(err, &missingErr) {
+			t.Fatalf("expected MissingFieldsError, got %v", err)
+		}
+
+		if !strings.HasPrefix(err.Error(), "record 2:") {
+			t.Errorf("expected error for record 2, got %q", err.Error())
+		}
+
+		if calls != 1 {
+			t.Errorf("expected 1 callback before the error, got %d", calls)
+		}
+	})
+
+	t.Run("returns callback errors", func(t *testing.T) {
+		// GIVEN: a callback that fails
+		errStop := errors.New("stop")
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"name\":\"a\",\"email\":\"a@x\"}\n"))
+
+		// WHEN: decoding the sequence
+		err := vital.DecodeJSONSeq(req, func(testUser) error { return errStop })
+
+		// THEN: the callback error is returned
+		if !errors.Is(err, errStop) {
+			t.Errorf("expected callback error, got %v", err)
+		}
+	})
+
+	t.Run("decodes records completed before a read failure", func(t *testing.T) {
+		// GIVEN: a stream that breaks in the middle of its third record
+		errBroken := errors.New("connection reset")
+		body := "{\"name\":\"a\",\"email\":\"a@x\"}\n{\"name\":\"b\",\"email\":\"b@x\"}\n{\"na"
+
+		req := httptest.NewRequest(http.MethodPost, "/", &failingReader{data: []byte(body), err: errBroken})
+
+		var names []string
+
+		// WHEN: decoding the sequence
+		err := vital.DecodeJSONSeq(req, func(user testUser) error {
+			names = append(names, user.Name)
+
+			return nil
+		})
+
+		// THEN: the complete records are decoded and the read error is returned
+		if strings.Join(names, ",") != "a,b" {
+			t.Errorf("expected records a,b, got %v", names)
+		}
+
+		if !errors.Is(err, errBroken) {
+			t.Errorf("expected the read error, got %v", err)
+		}
+	})
+
+	t.Run("accepts the largest record size", func(t *testing.T) {
+		// GIVEN: a stream with a single record
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"a","email":"a@x"}`))
+
+		// WHEN: decoding without a practical record size limit
+		err := vital.DecodeJSONSeq(req, func(testUser) error { return nil }, vital.WithMaxRecordSize(math.MaxInt64))
+
+		// THEN: the limit does not overflow
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("enforces size limits", func(t *testing.T) {
+		record := `{"name":"` + strings.Repeat("x", 100) + `","email":"a@x"}` + "\n"
+
+		tests := []struct {
+			name          string
+			opts          []vital.DecodeOption
+			expectedLimit int64
+		}{
+			{name: "per record", opts: []vital.DecodeOption{vital.WithMaxRecordSize(64)}, expectedLimit: 64},
+			{name: "overall", opts: []vital.DecodeOption{vital.WithMaxBodySize(200)}, expectedLimit: 200},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN: a stream exceeding the limit
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat(record, 3)))
+
+				// WHEN: decoding the sequence
+				err := vital.DecodeJSONSeq(req, func(testUser) error { return nil }, tt.opts...)
+
+				// THEN: a size error with the exceeded limit is returned
+				var sizeErr *vital.MaxBodySizeError
+				if !errors.As(err, &sizeErr) {
+					t.Fatalf("expected MaxBodySizeError, got %v", err)
+				}
+
+				if sizeErr.Limit != tt.expectedLimit {
+					t.Errorf("expected limit %d, got %d", tt.expectedLimit, sizeErr.Limit)
+				}
+			})
+		}
+	})
+}
+
+func TestReadBody(t *testing.T) {
+	t.Run("reads a small body", func(t *testing.T) {
+		// GIVEN: a request with a small raw body
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("raw payload"))
+
+		// WHEN: reading the body
+		data, err := vital.ReadBody(req)
+
+		// THEN: the full body is returned
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if string(data) != "raw payload" {
+			t.Errorf("expected %q, got %q", "raw payload", string(data))
+		}
+	})
+
+	t.Run("rejects a too large body", func(t *testing.T) {
+		// GIVEN: a request with a body over the limit
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("x", 101)))
+
+		// WHEN: reading the body with a 100 byte limit
+		_, err := vital.ReadBody(req, vital.WithMaxBodySize(100))
+
+		// THEN: a size error is returned that maps to 413
+		var sizeErr *vital.MaxBodySizeError
+		if !errors.As(err, &sizeErr) {
+			t.Fatalf("expected MaxBodySizeError, got %v", err)
+		}
+
+		if problem := vital.ProblemFromDecodeError(err); problem.Status != http.StatusRequestEntityTooLarge {
+			t.Errorf("expected status 413, got %d", problem.Status)
+		}
+	})
+
+	t.Run("accepts a body exactly at the limit", func(t *testing.T) {
+		// GIVEN: a request with a body of exactly the limit
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("x", 100)))
+
+		// WHEN: reading the body with a 100 byte limit
+		data, err := vital.ReadBody(req, vital.WithMaxBodySize(100))
+
+		// THEN: the body is returned
+		if err != nil || len(data) != 100 {
+			t.Errorf("expected 100 bytes without error, got %d bytes, %v", len(data), err)
+		}
+	})
+}
+
+func TestReadBody_OversizedWithResponseWriter(t *testing.T) {
+	// GIVEN: a server reading raw bodies with the response writer supplied
+	var readErr error
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = vital.ReadBody(r, vital.WithMaxBodySize(64), vital.WithResponseWriter(w))
+		if readErr != nil {
+			vital.RespondProblem(w, vital.ProblemFromDecodeError(readErr))
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	body := strings.NewReader(strings.Repeat("x", 1024))
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, server.URL, body)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	// WHEN: sending a body exceeding the limit
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	// THEN: the body is rejected with 413 and the connection is closed after the response
+	var sizeErr *vital.MaxBodySizeError
+	if !errors.As(readErr, &sizeErr) {
+		t.Errorf("expected MaxBodySizeError, got %v", readErr)
+	}
+
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413, got %d", resp.StatusCode)
+	}
+
+	if !resp.Close {
+		t.Error("expected the server to close the connection")
+	}
+}
+
 func TestDecodeForm_ValidForm(t *testing.T) {
 	// GIVEN: a request with valid form urlencoded body
 	formBody := "name=Alice&email=alice@example.com&age=30"
@@ -290,6 +1080,56 @@ func TestDecodeForm_ValidForm(t *testing.T) {
 	}
 }
 
+func TestDecodeForm_EmptyAsZero(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []vital.DecodeOption
+		expectErr bool
+	}{
+		{
+			name:      "empty optional int fails to parse by default",
+			expectErr: true,
+		},
+		{
+			name: "empty optional int is left as zero with WithEmptyAsZero",
+			opts: []vital.DecodeOption{vital.WithEmptyAsZero()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a form submitting the optional age field as an empty string
+			formBody := "name=Alice&email=alice@example.com&age="
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(formBody))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			// WHEN: decoding the form body
+			user, err := vital.DecodeForm[testUser](req, tt.opts...)
+
+			// THEN: the empty value is rejected or treated as not provided
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected a parse error for the empty age")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if user.Age != 0 {
+				t.Errorf("expected age 0, got %d", user.Age)
+			}
+
+			if user.Name != "Alice" {
+				t.Errorf("expected name 'Alice', got %q", user.Name)
+			}
+		})
+	}
+}
+
 func TestDecodeForm_MalformedForm(t *testing.T) {
 	// GIVEN: a request with malformed form data (invalid percent encoding)
 	malformedForm := "name=%ZZ&email=alice@example.com"
@@ -311,6 +1151,54 @@ func TestDecodeForm_MalformedForm(t *testing.T) {
 	}
 }
 
+func TestDecodeForm_OversizedWithResponseWriter(t *testing.T) {
+	// GIVEN: a server decoding forms with the response writer supplied
+	var decodeErr error
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, decodeErr = vital.DecodeForm[testUser](r, vital.WithMaxBodySize(64), vital.WithResponseWriter(w))
+		if decodeErr != nil {
+			vital.RespondProblem(w, vital.ProblemFromDecodeError(decodeErr))
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	body := "name=alice&email=" + strings.Repeat("a", 1024)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, server.URL, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// WHEN: sending a form body exceeding the limit
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	// THEN: the body is rejected with 413 and the connection is closed after the response
+	var sizeErr *vital.MaxBodySizeError
+	if !errors.As(decodeErr, &sizeErr) {
+		t.Errorf("expected MaxBodySizeError, got %v", decodeErr)
+	}
+
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413, got %d", resp.StatusCode)
+	}
+
+	if !resp.Close {
+		t.Error("expected the server to close the connection")
+	}
+}
+
 func TestDecodeForm_MissingRequiredFields(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -448,6 +1336,156 @@ func TestValidationError_ProblemDetailExtensions(t *testing.T) {
 	}
 }
 
+func TestDecodeJSONOrRespond(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		opts           []vital.DecodeOption
+		expectedOK     bool
+		expectedStatus int
+	}{
+		{
+			name:           "valid body",
+			body:           `{"name":"Alice","email":"alice@example.com"}`,
+			expectedOK:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "malformed JSON",
+			body:           `{"name":`,
+			expectedOK:     false,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing required fields",
+			body:           `{"age":30}`,
+			expectedOK:     false,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "body too large",
+			body:           `{"name":"` + strings.Repeat("x", 200) + `"}`,
+			opts:           []vital.DecodeOption{vital.WithMaxBodySize(100)},
+			expectedOK:     false,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a request with the given body
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			// WHEN: decoding or responding
+			user, ok := vital.DecodeJSONOrRespond[testUser](rec, req, tt.opts...)
+
+			// THEN: success returns the value, failure writes the matching problem
+			if ok != tt.expectedOK {
+				t.Fatalf("expected ok=%v, got %v", tt.expectedOK, ok)
+			}
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+
+			if ok {
+				if user.Name != "Alice" {
+					t.Errorf("expected name Alice, got %q", user.Name)
+				}
+
+				if rec.Body.Len() != 0 {
+					t.Errorf("expected nothing written on success, got %q", rec.Body.String())
+				}
+
+				return
+			}
+
+			if contentType := rec.Header().Get("Content-Type"); contentType != "application/problem+json" {
+				t.Errorf("expected problem content type, got %q", contentType)
+			}
+		})
+	}
+}
+
+func TestProblemFromDecodeError(t *testing.T) {
+	t.Run("missing fields map to 422 with per-field errors", func(t *testing.T) {
+		// GIVEN: a decode error for missing required fields
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"age":30}`))
+
+		_, err := vital.DecodeJSON[testUser](req)
+
+		var missingErr *vital.MissingFieldsError
+		if !errors.As(err, &missingErr) {
+			t.Fatalf("expected MissingFieldsError, got %v", err)
+		}
+
+		// WHEN: converting the error to a problem
+		problem := vital.ProblemFromDecodeError(err)
+
+		// THEN: it should be a 422 listing each missing field
+		if problem.Status != http.StatusUnprocessableEntity {
+			t.Errorf("expected status 422, got %d", problem.Status)
+		}
+
+		data, err := json.Marshal(problem)
+		if err != nil {
+			t.Fatalf("failed to marshal problem: %v", err)
+		}
+
+		expected := `"errors":[{"field":"name","reason":"required"},{"field":"email","reason":"required"}]`
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("expected %s in problem, got %s", expected, data)
+		}
+	})
+
+	t.Run("too large body maps to 413", func(t *testing.T) {
+		// GIVEN: a decode error for an oversized body
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"`+strings.Repeat("x", 200)+`"}`))
+
+		_, err := vital.DecodeJSON[testUser](req, vital.WithMaxBodySize(100))
+
+		var sizeErr *vital.MaxBodySizeError
+		if !errors.As(err, &sizeErr) {
+			t.Fatalf("expected MaxBodySizeError, got %v", err)
+		}
+
+		// WHEN: converting the error to a problem
+		problem := vital.ProblemFromDecodeError(err)
+
+		// THEN: it should be a 413
+		if problem.Status != http.StatusRequestEntityTooLarge {
+			t.Errorf("expected status 413, got %d", problem.Status)
+		}
+	})
+
+	t.Run("other errors map to expected statuses", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			err            error
+			expectedStatus int
+		}{
+			{"invalid JSON", errors.New("invalid JSON: unexpected EOF"), http.StatusBadRequest},
+			{"unsupported media type", vital.ErrUnsupportedMediaType, http.StatusUnsupportedMediaType},
+			{"request canceled", vital.ErrRequestCanceled, vital.StatusClientClosedRequest},
+		}
+
+		for _, tt := range tests {
+			// WHEN: converting the error to a problem
+			problem := vital.ProblemFromDecodeError(tt.err)
+
+			// THEN: it should have the expected status
+			if problem.Status != tt.expectedStatus {
+				t.Errorf("%s: expected status %d, got %d", tt.name, tt.expectedStatus, problem.Status)
+			}
+		}
+
+		if vital.ProblemFromDecodeError(nil) != nil {
+			t.Error("expected nil problem for nil error")
+		}
+	})
+}
+
 func TestDecodeJSON_InHandler(t *testing.T) {
 	// GIVEN: an HTTP handler that uses DecodeJSON
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,98 @@
+package vital
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// expiredDate is an Expires value in the past, marking a response as already stale.
+const expiredDate = "Thu, 01 Jan 1970 00:00:00 GMT"
+
+// CacheControl returns a middleware that sets the Cache-Control header to directive before the
+// handler runs, so handlers can override it. A matching Expires header is derived for HTTP/1.0
+// caches: now plus max-age when the directive has one, or a date in the past for no-store and no-cache.
+// The derived Expires header is dropped when the handler overrides Cache-Control.
+func CacheControl(directive string) Middleware {
+	return func(next http.Handler) http.Handler {
+		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", directive)
+
+			derived := make(map[string]string)
+
+			expires := expiresFor(directive, time.Now())
+			if expires != "" {
+				w.Header().Set("Expires", expires)
+				derived["Expires"] = expires
+			}
+
+			serveWithCacheHeaders(w, r, next, derived)
+		})
+	}
+}
+
+// NoStore returns a middleware that disables caching with the same headers as the health endpoints:
+// "Cache-Control: no-store, no-cache", "Pragma: no-cache", and an Expires date in the past.
+// Pragma and Expires are dropped when the handler overrides Cache-Control.
+func NoStore() Middleware {
+	return func(next http.Handler) http.Handler {
+		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			disableResponseCacheHeaders(w)
+
+			serveWithCacheHeaders(w, r, next, map[string]string{
+				"Pragma":  w.Header().Get("Pragma"),
+				"Expires": w.Header().Get("Expires"),
+			})
+		})
+	}
+}
+
+// serveWithCacheHeaders serves next and, if the handler replaced the Cache-Control header set
+// before it ran, removes the derived headers it left unchanged, so a stale Expires or Pragma
+// cannot contradict the handler's own directive.
+func serveWithCacheHeaders(w http.ResponseWriter, r *http.Request, next http.Handler, derived map[string]string) {
+	directive := w.Header().Get("Cache-Control")
+
+	wrapped, rw := wrapResponseWriter(w)
+	wrapped.beforeHeader = func(header http.Header) {
+		if header.Get("Cache-Control") == directive {
+			return
+		}
+
+		for name, value := range derived {
+			if header.Get(name) == value {
+				header.Del(name)
+			}
+		}
+	}
+
+	next.ServeHTTP(rw, r)
+
+	// Headers of a handler that wrote nothing are sent after it returns.
+	wrapped.captureHeader()
+}
+
+// expiresFor derives an Expires header value from a Cache-Control directive,
+// or returns an empty string if the directive has no expiry.
+func expiresFor(directive string, now time.Time) string {
+	for part := range strings.SplitSeq(directive, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+
+		switch strings.ToLower(name) {
+		case "no-store", "no-cache":
+			return expiredDate
+		case "max-age":
+			seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+			if err != nil {
+				return ""
+			}
+
+			return now.Add(time.Duration(seconds) * time.Second).UTC().Format(http.TimeFormat)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,116 @@
+package vital_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/monkescience/vital"
+)
+
+func TestCacheControl(t *testing.T) {
+	tests := []struct {
+		name                 string
+		middleware           vital.Middleware
+		override             string
+		silent               bool
+		expectedCacheControl string
+		expectedPragma       string
+		checkExpires         func(t *testing.T, expires string)
+	}{
+		{
+			name:                 "max-age sets a future Expires",
+			middleware:           vital.CacheControl("public, max-age=3600"),
+			expectedCacheControl: "public, max-age=3600",
+			checkExpires: func(t *testing.T, expires string) {
+				t.Helper()
+
+				parsed, err := http.ParseTime(expires)
+				if err != nil {
+					t.Fatalf("invalid Expires %q: %v", expires, err)
+				}
+
+				if until := time.Until(parsed); until < 59*time.Minute || until > 61*time.Minute {
+					t.Errorf("expected Expires about one hour ahead, got %v", until)
+				}
+			},
+		},
+		{
+			name:                 "no-store expires immediately",
+			middleware:           vital.NoStore(),
+			expectedCacheControl: "no-store, no-cache",
+			expectedPragma:       "no-cache",
+			checkExpires: func(t *testing.T, expires string) {
+				t.Helper()
+
+				if expires != "Thu, 01 Jan 1970 00:00:00 GMT" {
+					t.Errorf("expected Expires in the past, got %q", expires)
+				}
+			},
+		},
+		{
+			name:                 "handler can override the directive",
+			middleware:           vital.CacheControl("no-store"),
+			override:             "private, max-age=60",
+			expectedCacheControl: "private, max-age=60",
+			checkExpires:         expectNoExpires,
+		},
+		{
+			name:                 "override without writing drops the derived Expires",
+			middleware:           vital.CacheControl("public, max-age=3600"),
+			override:             "no-cache",
+			silent:               true,
+			expectedCacheControl: "no-cache",
+			checkExpires:         expectNoExpires,
+		},
+		{
+			name:                 "override drops the no-store Pragma and Expires",
+			middleware:           vital.NoStore(),
+			override:             "public, max-age=60",
+			expectedCacheControl: "public, max-age=60",
+			checkExpires:         expectNoExpires,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a cache middleware wrapping a handler
+			handler := tt.middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if tt.override != "" {
+					w.Header().Set("Cache-Control", tt.override)
+				}
+
+				if !tt.silent {
+					w.WriteHeader(http.StatusOK)
+				}
+			}))
+
+			rec := httptest.NewRecorder()
+
+			// WHEN: the request is processed
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			// THEN: the caching headers are set as expected
+			if got := rec.Header().Get("Cache-Control"); got != tt.expectedCacheControl {
+				t.Errorf("expected Cache-Control %q, got %q", tt.expectedCacheControl, got)
+			}
+
+			if got := rec.Header().Get("Pragma"); got != tt.expectedPragma {
+				t.Errorf("expected Pragma %q, got %q", tt.expectedPragma, got)
+			}
+
+			if tt.checkExpires != nil {
+				tt.checkExpires(t, rec.Header().Get("Expires"))
+			}
+		})
+	}
+}
+
+func expectNoExpires(t *testing.T, expires string) {
+	t.Helper()
+
+	if expires != "" {
+		t.Errorf("expected no Expires, got %q", expires)
+	}
+}
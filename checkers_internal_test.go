@@ -0,0 +1,60 @@
+package vital
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitteredCacheChecker_TTLWithinBounds(t *testing.T) {
+	tests := []struct {
+		name   string
+		ttl    time.Duration
+		jitter time.Duration
+		minTTL time.Duration
+		maxTTL time.Duration
+	}{
+		{
+			name:   "ttl varies within jitter",
+			ttl:    10 * time.Second,
+			jitter: 2 * time.Second,
+			minTTL: 8 * time.Second,
+			maxTTL: 12 * time.Second,
+		},
+		{
+			name:   "jitter is capped at ttl",
+			ttl:    time.Second,
+			jitter: time.Minute,
+			minTTL: 0,
+			maxTTL: 2 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a jittered cache checker
+			checker, ok := JitteredCacheChecker(nil, tt.ttl, tt.jitter).(*jitteredCacheChecker)
+			if !ok {
+				t.Fatal("expected *jitteredCacheChecker")
+			}
+
+			// WHEN: drawing many effective TTLs
+			seen := make(map[time.Duration]struct{})
+
+			for range 1000 {
+				ttl := checker.nextTTL()
+
+				// THEN: every TTL stays within the bounds
+				if ttl < tt.minTTL || ttl > tt.maxTTL {
+					t.Fatalf("expected TTL in [%s, %s], got %s", tt.minTTL, tt.maxTTL, ttl)
+				}
+
+				seen[ttl] = struct{}{}
+			}
+
+			// THEN: the TTL actually varies
+			if len(seen) < 2 {
+				t.Errorf("expected varying TTLs, got %d distinct values", len(seen))
+			}
+		})
+	}
+}
@@ -0,0 +1,742 @@
+package vital_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/monkescience/vital"
+)
+
+// flakyChecker fails a fixed number of times before succeeding.
+type flakyChecker struct {
+	name     string
+	failures int32
+	calls    atomic.Int32
+}
+
+func (f *flakyChecker) Name() string {
+	return f.name
+}
+
+func (f *flakyChecker) Check(_ context.Context) (vital.Status, string) {
+	if f.calls.Add(1) <= f.failures {
+		return vital.StatusError, "transient failure"
+	}
+
+	return vital.StatusOK, "recovered"
+}
+
+func TestRetryChecker(t *testing.T) {
+	t.Run("succeeds after a transient failure", func(t *testing.T) {
+		// GIVEN: a checker that fails once then succeeds
+		inner := &flakyChecker{name: "flaky", failures: 1}
+		checker := vital.RetryChecker(inner, 3, 10*time.Millisecond)
+
+		// WHEN: running the check
+		status, msg := checker.Check(context.Background())
+
+		// THEN: it should ultimately report OK
+		if status != vital.StatusOK {
+			t.Errorf("expected status %v, got %v (%s)", vital.StatusOK, status, msg)
+		}
+
+		if inner.calls.Load() != 2 {
+			t.Errorf("expected 2 attempts, got %d", inner.calls.Load())
+		}
+
+		if checker.Name() != "flaky" {
+			t.Errorf("expected name %q, got %q", "flaky", checker.Name())
+		}
+	})
+
+	t.Run("returns last result after exhausting attempts", func(t *testing.T) {
+		// GIVEN: a checker that always fails
+		inner := &flakyChecker{name: "broken", failures: 100}
+		checker := vital.RetryChecker(inner, 3, time.Millisecond)
+
+		// WHEN: running the check
+		status, msg := checker.Check(context.Background())
+
+		// THEN: it should report the last error after all attempts
+		if status != vital.StatusError {
+			t.Errorf("expected status %v, got %v", vital.StatusError, status)
+		}
+
+		if msg != "transient failure" {
+			t.Errorf("expected message %q, got %q", "transient failure", msg)
+		}
+
+		if inner.calls.Load() != 3 {
+			t.Errorf("expected 3 attempts, got %d", inner.calls.Load())
+		}
+	})
+
+	t.Run("stays within the context deadline", func(t *testing.T) {
+		// GIVEN: a failing checker with a backoff longer than the deadline
+		inner := &flakyChecker{name: "broken", failures: 100}
+		checker := vital.RetryChecker(inner, 5, time.Second)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		// WHEN: running the check
+		start := time.Now()
+		status, _ := checker.Check(ctx)
+		elapsed := time.Since(start)
+
+		// THEN: it should stop early without waiting for the backoff
+		if status != vital.StatusError {
+			t.Errorf("expected status %v, got %v", vital.StatusError, status)
+		}
+
+		if elapsed > 100*time.Millisecond {
+			t.Errorf("expected retries to respect the deadline, took %v", elapsed)
+		}
+
+		if inner.calls.Load() != 1 {
+			t.Errorf("expected 1 attempt, got %d", inner.calls.Load())
+		}
+	})
+}
+
+func TestDependencyChecker(t *testing.T) {
+	t.Run("reports OK when dependency is ready", func(t *testing.T) {
+		// GIVEN: a dependency whose readiness endpoint returns 200
+		dependency := httptest.NewServer(vital.NewHealthHandler())
+		defer dependency.Close()
+
+		checker := vital.DependencyChecker("service-b", dependency.URL+"/health/ready")
+
+		// WHEN: running the check
+		status, msg := checker.Check(context.Background())
+
+		// THEN: it should report OK
+		if status != vital.StatusOK {
+			t.Errorf("expected status %v, got %v (%s)", vital.StatusOK, status, msg)
+		}
+
+		if checker.Name() != "service-b" {
+			t.Errorf("expected name %q, got %q", "service-b", checker.Name())
+		}
+	})
+
+	t.Run("reports error with failing sub-checks", func(t *testing.T) {
+		// GIVEN: a dependency whose readiness endpoint returns 503
+		dependency := httptest.NewServer(vital.NewHealthHandler(
+			vital.WithCheckers(&mockChecker{name: "database", status: vital.StatusError, message: "connection refused"}),
+		))
+		defer dependency.Close()
+
+		checker := vital.DependencyChecker(
+			"service-b",
+			dependency.URL+"/health/ready",
+			vital.WithDependencyCheckDetails(),
+		)
+
+		// WHEN: running the check
+		status, msg := checker.Check(context.Background())
+
+		// THEN: it should report the status code and failing sub-check
+		if status != vital.StatusError {
+			t.Errorf("expected status %v, got %v", vital.StatusError, status)
+		}
+
+		if !strings.Contains(msg, "503") || !strings.Contains(msg, "database (connection refused)") {
+			t.Errorf("expected message with status and failing check, got %q", msg)
+		}
+	})
+
+	t.Run("does not chain deeper than one hop", func(t *testing.T) {
+		// GIVEN: service C is down and service B depends on it
+		serviceC := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer serviceC.Close()
+
+		serviceB := httptest.NewServer(vital.NewHealthHandler(
+			vital.WithCheckers(vital.DependencyChecker("service-c", serviceC.URL)),
+		))
+		defer serviceB.Close()
+
+		checker := vital.DependencyChecker("service-b", serviceB.URL+"/health/ready")
+
+		// WHEN: service A checks service B
+		status, msg := checker.Check(context.Background())
+
+		// THEN: service B should skip its own dependency checks
+		if status != vital.StatusOK {
+			t.Errorf("expected status %v, got %v (%s)", vital.StatusOK, status, msg)
+		}
+	})
+}
+
+func TestFreshnessChecker(t *testing.T) {
+	tests := []struct {
+		name           string
+		lastUpdate     time.Time
+		expectedStatus vital.Status
+		expectedMsg    string
+	}{
+		{
+			name:           "recent update is ok",
+			lastUpdate:     time.Now().Add(-10 * time.Second),
+			expectedStatus: vital.StatusOK,
+		},
+		{
+			name:           "stale update fails",
+			lastUpdate:     time.Now().Add(-5 * time.Minute),
+			expectedStatus: vital.StatusError,
+			expectedMsg:    "exceeds max age 1m0s",
+		},
+		{
+			name:           "missing update fails",
+			expectedStatus: vital.StatusError,
+			expectedMsg:    "no update received",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a freshness checker with a one minute staleness window
+			checker := vital.FreshnessChecker("config", func() time.Time { return tt.lastUpdate }, time.Minute)
+
+			// WHEN: running the check
+			status, msg := checker.Check(context.Background())
+
+			// THEN: the status reflects the age of the last update
+			if status != tt.expectedStatus {
+				t.Errorf("expected status %v, got %v (%s)", tt.expectedStatus, status, msg)
+			}
+
+			if !strings.Contains(msg, tt.expectedMsg) {
+				t.Errorf("expected message containing %q, got %q", tt.expectedMsg, msg)
+			}
+		})
+	}
+}
+
+// countingChecker counts how often it is run.
+type countingChecker struct {
+	calls atomic.Int32
+	// gate, when set, blocks checks until it is closed or the context ends
+	gate chan struct{}
+}
+
+func (c *countingChecker) Name() string {
+	return "counting"
+}
+
+func (c *countingChecker) Check(ctx context.Context) (vital.Status, string) {
+	c.calls.Add(1)
+
+	if c.gate != nil {
+		select {
+		case <-c.gate:
+		case <-ctx.Done():
+			return vital.StatusError, ctx.Err().Error()
+		}
+	}
+
+	return vital.StatusOK, "checked"
+}
+
+func TestJitteredCacheChecker(t *testing.T) {
+	t.Run("serves cached result within the window", func(t *testing.T) {
+		// GIVEN: a jittered cache with a window far longer than the test
+		inner := &countingChecker{}
+		checker := vital.JitteredCacheChecker(inner, time.Minute, 10*time.Second)
+
+		// WHEN: checking repeatedly
+		for range 5 {
+			status, msg := checker.Check(context.Background())
+			if status != vital.StatusOK || msg != "checked" {
+				t.Fatalf("expected cached ok result, got %v (%s)", status, msg)
+			}
+		}
+
+		// THEN: the inner checker ran only once
+		if calls := inner.calls.Load(); calls != 1 {
+			t.Errorf("expected 1 inner call, got %d", calls)
+		}
+	})
+
+	t.Run("re-runs after the window expires", func(t *testing.T) {
+		// GIVEN: a jittered cache with a tiny window
+		inner := &countingChecker{}
+		checker := vital.JitteredCacheChecker(inner, time.Millisecond, 0)
+
+		// WHEN: checking again after the window passed
+		checker.Check(context.Background())
+		time.Sleep(5 * time.Millisecond)
+		checker.Check(context.Background())
+
+		// THEN: the inner checker ran again
+		if calls := inner.calls.Load(); calls != 2 {
+			t.Errorf("expected 2 inner calls, got %d", calls)
+		}
+	})
+
+	t.Run("does not cache checks that exceed the deadline", func(t *testing.T) {
+		// GIVEN: a jittered cache around a check that blocks until released
+		inner := &countingChecker{gate: make(chan struct{})}
+		checker := vital.JitteredCacheChecker(inner, time.Minute, 0)
+
+		check := func() vital.Status {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			status, _ := checker.Check(ctx)
+
+			return status
+		}
+
+		// WHEN: checking twice with a deadline that passes
+		status := check()
+		time.Sleep(5 * time.Millisecond)
+		check()
+
+		// THEN: the timed out check failed and the second check ran the inner checker again
+		if status != vital.StatusError {
+			t.Errorf("expected the timed out check to fail, got %v", status)
+		}
+
+		if calls := inner.calls.Load(); calls != 2 {
+			t.Errorf("expected 2 inner calls, got %d", calls)
+		}
+	})
+
+	t.Run("reports a panicking check", func(t *testing.T) {
+		// GIVEN: a jittered cache around a checker that panics
+		checker := vital.JitteredCacheChecker(&panickingChecker{name: "cache", message: "boom"}, time.Minute, 0)
+
+		// WHEN: checking twice
+		status, msg := checker.Check(context.Background())
+		cachedStatus, cachedMsg := checker.Check(context.Background())
+
+		// THEN: the panic is reported as an error instead of crashing the process
+		if status != vital.StatusError || !strings.Contains(msg, "boom") {
+			t.Errorf("expected the panic to be reported, got %v (%s)", status, msg)
+		}
+
+		if cachedStatus != status || cachedMsg != msg {
+			t.Errorf("expected the cached result, got %v (%s)", cachedStatus, cachedMsg)
+		}
+	})
+
+	t.Run("shares one inner call and lets callers give up", func(t *testing.T) {
+		// GIVEN: a jittered cache around a check that blocks until released
+		inner := &countingChecker{gate: make(chan struct{})}
+		checker := vital.JitteredCacheChecker(inner, time.Minute, 0)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		go func() {
+			for inner.calls.Load() == 0 {
+				time.Sleep(time.Millisecond)
+			}
+
+			cancel()
+		}()
+
+		// WHEN: the caller that started the check gives up, and others wait for it
+		status, msg := checker.Check(ctx)
+
+		var waitGroup sync.WaitGroup
+
+		results := make(chan string, 3)
+
+		for range 3 {
+			waitGroup.Go(func() {
+				_, shared := checker.Check(context.Background())
+				results <- shared
+			})
+		}
+
+		close(inner.gate)
+		waitGroup.Wait()
+		close(results)
+
+		// THEN: the cancelled caller returns early and the others share the single inner call
+		if status != vital.StatusError || msg != context.Canceled.Error() {
+			t.Errorf("expected the cancelled caller to fail with %q, got %v (%s)", context.Canceled, status, msg)
+		}
+
+		for msg := range results {
+			if msg != "checked" {
+				t.Errorf("expected the shared result, got %q", msg)
+			}
+		}
+
+		if calls := inner.calls.Load(); calls != 1 {
+			t.Errorf("expected 1 inner call, got %d", calls)
+		}
+	})
+}
+
+func TestDNSChecker(t *testing.T) {
+	tests := []struct {
+		name           string
+		host           string
+		expectedStatus vital.Status
+		expectedMsg    string
+	}{
+		{
+			name:           "resolvable host",
+			host:           "localhost",
+			expectedStatus: vital.StatusOK,
+			expectedMsg:    "resolved localhost",
+		},
+		{
+			name:           "invalid TLD",
+			host:           "vital-dns-check.invalid",
+			expectedStatus: vital.StatusError,
+			expectedMsg:    "failed to resolve vital-dns-check.invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a DNS checker for the host
+			checker := vital.DNSChecker("dns", tt.host)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			// WHEN: running the check
+			status, msg := checker.Check(ctx)
+
+			// THEN: the status reflects whether the host resolved
+			if status != tt.expectedStatus {
+				t.Errorf("expected status %v, got %v (%s)", tt.expectedStatus, status, msg)
+			}
+
+			if !strings.Contains(msg, tt.expectedMsg) {
+				t.Errorf("expected message containing %q, got %q", tt.expectedMsg, msg)
+			}
+		})
+	}
+}
+
+func TestWritableDirChecker(t *testing.T) {
+	t.Run("writable directory passes", func(t *testing.T) {
+		// GIVEN: a writable temporary directory
+		dir := t.TempDir()
+		checker := vital.WritableDirChecker("data", dir)
+
+		// WHEN: running the check
+		status, msg := checker.Check(context.Background())
+
+		// THEN: the check passes and leaves no file behind
+		if status != vital.StatusOK {
+			t.Errorf("expected status ok, got %v (%s)", status, msg)
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("failed to read dir: %v", err)
+		}
+
+		if len(entries) != 0 {
+			t.Errorf("expected temp file to be removed, found %d entries", len(entries))
+		}
+	})
+
+	t.Run("read-only directory fails", func(t *testing.T) {
+		if os.Geteuid() == 0 {
+			t.Skip("root can write to read-only directories")
+		}
+
+		// GIVEN: a read-only directory
+		dir := t.TempDir()
+
+		err := os.Chmod(dir, 0o500)
+		if err != nil {
+			t.Fatalf("failed to make dir read-only: %v", err)
+		}
+
+		t.Cleanup(func() { _ = os.Chmod(dir, 0o700) })
+
+		checker := vital.WritableDirChecker("data", dir)
+
+		// WHEN: running the check
+		status, msg := checker.Check(context.Background())
+
+		// THEN: the check fails with the OS error
+		if status != vital.StatusError || !strings.Contains(msg, "permission denied") {
+			t.Errorf("expected permission error, got %v (%s)", status, msg)
+		}
+	})
+
+	t.Run("missing directory fails", func(t *testing.T) {
+		// GIVEN: a directory that does not exist
+		checker := vital.WritableDirChecker("data", filepath.Join(t.TempDir(), "missing"))
+
+		// WHEN: running the check
+		status, msg := checker.Check(context.Background())
+
+		// THEN: the check fails with the OS error
+		if status != vital.StatusError || !strings.Contains(msg, "failed to create file") {
+			t.Errorf("expected create error, got %v (%s)", status, msg)
+		}
+	})
+}
+
+func TestPingChecker(t *testing.T) {
+	tests := []struct {
+		name           string
+		ping           func(ctx context.Context) error
+		expectedStatus vital.Status
+		expectedMsg    string
+	}{
+		{
+			name:           "passing ping",
+			ping:           func(_ context.Context) error { return nil },
+			expectedStatus: vital.StatusOK,
+		},
+		{
+			name:           "failing ping",
+			ping:           func(_ context.Context) error { return errors.New("broker unreachable") },
+			expectedStatus: vital.StatusError,
+			expectedMsg:    "broker unreachable",
+		},
+		{
+			name:           "ping honors context",
+			ping:           func(ctx context.Context) error { return ctx.Err() },
+			expectedStatus: vital.StatusError,
+			expectedMsg:    context.Canceled.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a ping checker and a context cancelled for the context case
+			checker := vital.PingChecker("kafka", tt.ping)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.expectedMsg == context.Canceled.Error() {
+				cancel()
+			}
+
+			defer cancel()
+
+			// WHEN: running the check
+			status, msg := checker.Check(ctx)
+
+			// THEN: the status and message reflect the ping result
+			if status != tt.expectedStatus {
+				t.Errorf("expected status %v, got %v (%s)", tt.expectedStatus, status, msg)
+			}
+
+			if msg != tt.expectedMsg {
+				t.Errorf("expected message %q, got %q", tt.expectedMsg, msg)
+			}
+
+			if checker.Name() != "kafka" {
+				t.Errorf("expected name %q, got %q", "kafka", checker.Name())
+			}
+		})
+	}
+}
+
+// panickingChecker panics with its message whenever it is checked.
+type panickingChecker struct {
+	name    string
+	message string
+}
+
+func (p *panickingChecker) Name() string {
+	return p.name
+}
+
+func (p *panickingChecker) Check(_ context.Context) (vital.Status, string) {
+	panic(p.message)
+}
+
+func TestSafeChecker(t *testing.T) {
+	// GIVEN: a readiness handler with a panicking checker wrapped in SafeChecker
+	checker := vital.SafeChecker(&panickingChecker{name: "community", message: "nil map write"})
+	handler := vital.ReadyHandlerFunc("1.0.0", "test", []vital.Checker{checker})
+
+	rec := httptest.NewRecorder()
+
+	// WHEN: calling the ready endpoint
+	handler(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+	// THEN: the panic is reported as a failing check instead of crashing
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", rec.Code)
+	}
+
+	var response vital.ReadyResponse
+
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if len(response.Checks) != 1 {
+		t.Fatalf("expected one check, got: %s", rec.Body.String())
+	}
+
+	check := response.Checks[0]
+	if check.Name != "community" || check.Status != vital.StatusError || check.Message != "check panicked: nil map write" {
+		t.Errorf("expected panicked check entry, got %+v", check)
+	}
+}
+
+func TestAnyChecker(t *testing.T) {
+	up := func(_ context.Context) error { return nil }
+	down := func(_ context.Context) error { return errors.New("connection refused") }
+	hanging := func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	}
+
+	tests := []struct {
+		name           string
+		members        []vital.Checker
+		expectedStatus vital.Status
+		expectedMsg    string
+	}{
+		{
+			name: "one of two members ok",
+			members: []vital.Checker{
+				vital.PingChecker("cache-1", down),
+				vital.PingChecker("cache-2", up),
+			},
+			expectedStatus: vital.StatusOK,
+		},
+		{
+			name: "all members down",
+			members: []vital.Checker{
+				vital.PingChecker("cache-1", down),
+				vital.PingChecker("cache-2", down),
+			},
+			expectedStatus: vital.StatusError,
+			expectedMsg:    "all members down: cache-1: connection refused; cache-2: connection refused",
+		},
+		{
+			name: "ok member stops hanging members",
+			members: []vital.Checker{
+				vital.PingChecker("cache-1", hanging),
+				vital.PingChecker("cache-2", up),
+			},
+			expectedStatus: vital.StatusOK,
+		},
+		{
+			name: "hanging member is reported when the deadline passes",
+			members: []vital.Checker{
+				vital.PingChecker("cache-1", down),
+				vital.PingChecker("cache-2", hanging),
+			},
+			expectedStatus: vital.StatusError,
+			expectedMsg:    "all members down: cache-1: connection refused; cache-2: " + context.DeadlineExceeded.Error(),
+		},
+		{
+			name:           "no members",
+			expectedStatus: vital.StatusError,
+			expectedMsg:    "no members configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: an any checker over redundant members and a check deadline
+			checker := vital.AnyChecker("cache", tt.members...)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			// WHEN: running the check
+			status, msg := checker.Check(ctx)
+
+			// THEN: the check passes when any member is ok and lists the members that are down
+			if status != tt.expectedStatus {
+				t.Errorf("expected status %v, got %v (%s)", tt.expectedStatus, status, msg)
+			}
+
+			if msg != tt.expectedMsg {
+				t.Errorf("expected message %q, got %q", tt.expectedMsg, msg)
+			}
+
+			if checker.Name() != "cache" {
+				t.Errorf("expected name %q, got %q", "cache", checker.Name())
+			}
+		})
+	}
+}
+
+func TestClockSkewChecker(t *testing.T) {
+	tests := []struct {
+		name           string
+		reference      func(ctx context.Context) (time.Time, error)
+		expectedStatus vital.Status
+		expectedMsg    string
+	}{
+		{
+			name: "reference within the maximum skew",
+			reference: func(_ context.Context) (time.Time, error) {
+				return time.Now().Add(200 * time.Millisecond), nil
+			},
+			expectedStatus: vital.StatusOK,
+		},
+		{
+			name: "local clock ahead of the reference",
+			reference: func(_ context.Context) (time.Time, error) {
+				return time.Now().Add(-5 * time.Second), nil
+			},
+			expectedStatus: vital.StatusError,
+			expectedMsg:    "exceeds 1s",
+		},
+		{
+			name: "local clock behind the reference",
+			reference: func(_ context.Context) (time.Time, error) {
+				return time.Now().Add(5 * time.Second), nil
+			},
+			expectedStatus: vital.StatusError,
+			expectedMsg:    "exceeds 1s",
+		},
+		{
+			name: "reference fails",
+			reference: func(_ context.Context) (time.Time, error) {
+				return time.Time{}, errors.New("ntp timeout")
+			},
+			expectedStatus: vital.StatusError,
+			expectedMsg:    "failed to get reference time: ntp timeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a clock skew checker allowing one second of skew
+			checker := vital.ClockSkewChecker("clock", tt.reference, time.Second)
+
+			// WHEN: running the check
+			status, msg := checker.Check(context.Background())
+
+			// THEN: the status reflects whether the skew is within bounds
+			if status != tt.expectedStatus {
+				t.Errorf("expected status %v, got %v (%s)", tt.expectedStatus, status, msg)
+			}
+
+			if !strings.Contains(msg, tt.expectedMsg) {
+				t.Errorf("expected message containing %q, got %q", tt.expectedMsg, msg)
+			}
+
+			if checker.Name() != "clock" {
+				t.Errorf("expected name %q, got %q", "clock", checker.Name())
+			}
+		})
+	}
+}
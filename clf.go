@@ -0,0 +1,117 @@
+package vital
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clfTimeFormat is the timestamp layout of the Common Log Format.
+const clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// clfEscaper escapes quotes and backslashes inside quoted CLF fields.
+//
+//nolint:gochecknoglobals // Stateless, concurrency-safe replacer
+var clfEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// CLFLogger returns a middleware that writes one access log line per request to out in the
+// Apache Combined Log Format, for tooling that does not understand structured logs:
+//
+//	host ident authuser [date] "request" status bytes "referer" "agent"
+//
+// The host is the remote address without port, authuser is the Basic Auth user name with
+// whitespace, control characters, quotes, and backslashes escaped, since clients choose it
+// freely, and missing values are written as "-". Lines are written atomically.
+func CLFLogger(out io.Writer) Middleware {
+	var mu sync.Mutex
+
+	return func(next http.Handler) http.Handler {
+		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			wrapped, writer := wrapResponseWriter(w)
+
+			next.ServeHTTP(writer, r)
+
+			line := formatCLFLine(r, start, wrapped.statusCode, wrapped.bytesWritten)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			_, _ = io.WriteString(out, line)
+		})
+	}
+}
+
+// formatCLFLine formats a Combined Log Format line terminated by a newline.
+func formatCLFLine(r *http.Request, start time.Time, status int, bytesWritten int64) string {
+	user, _, _ := r.BasicAuth()
+
+	uri := r.RequestURI
+	if uri == "" {
+		uri = r.URL.RequestURI()
+	}
+
+	size := "-"
+	if bytesWritten > 0 {
+		size = strconv.FormatInt(bytesWritten, 10)
+	}
+
+	var b strings.Builder
+
+	b.WriteString(clfField(stripPort(r.RemoteAddr)))
+	b.WriteString(" - ")
+	b.WriteString(clfEscapeToken(clfField(user)))
+	b.WriteString(" [")
+	b.WriteString(start.Format(clfTimeFormat))
+	b.WriteString(`] "`)
+	b.WriteString(clfEscaper.Replace(r.Method + " " + uri + " " + r.Proto))
+	b.WriteString(`" `)
+	b.WriteString(strconv.Itoa(status))
+	b.WriteString(" ")
+	b.WriteString(size)
+	b.WriteString(` "`)
+	b.WriteString(clfEscaper.Replace(clfField(r.Referer())))
+	b.WriteString(`" "`)
+	b.WriteString(clfEscaper.Replace(clfField(r.UserAgent())))
+	b.WriteString("\"\n")
+
+	return b.String()
+}
+
+// clfEscapeToken escapes an unquoted field so it cannot contain spaces or line breaks:
+// whitespace and control characters become \xHH, and quotes and backslashes are escaped
+// with a backslash, as Apache does.
+func clfEscapeToken(value string) string {
+	var b strings.Builder
+
+	for i := range len(value) {
+		c := value[i]
+
+		switch {
+		case c <= ' ' || c == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, c)
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// clfField returns value, or "-" when it is empty.
+func clfField(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
@@ -0,0 +1,87 @@
+package vital_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/monkescience/vital"
+)
+
+func TestCLFLogger(t *testing.T) {
+	// GIVEN: a CLF logger writing to a buffer
+	var buf bytes.Buffer
+
+	handler := vital.CLFLogger(&buf)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("hello"))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/users?active=true", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.SetBasicAuth("frank", "secret")
+	req.Header.Set("Referer", "https://example.com/")
+	req.Header.Set("User-Agent", `curl/8.0 "quoted"`)
+
+	// WHEN: the request is served
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// THEN: a well-formed Combined Log Format line is written
+	pattern := regexp.MustCompile(
+		`^203\.0\.113\.7 - frank \[\d{2}/[A-Z][a-z]{2}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] ` +
+			`"POST /users\?active=true HTTP/1\.1" 201 5 "https://example\.com/" "curl/8\.0 \\"quoted\\""\n$`,
+	)
+
+	if !pattern.MatchString(buf.String()) {
+		t.Errorf("expected Combined Log Format line, got: %q", buf.String())
+	}
+}
+
+func TestCLFLogger_EscapesUserName(t *testing.T) {
+	// GIVEN: a CLF logger and a Basic Auth user name forging a second log line
+	var buf bytes.Buffer
+
+	handler := vital.CLFLogger(&buf)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.SetBasicAuth("eve\n10.0.0.1 - admin \"x", "wrong")
+
+	// WHEN: the request is served
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// THEN: the user name is escaped into a single field of a single line
+	pattern := regexp.MustCompile(
+		`^192\.0\.2\.1 - eve\\x0a10\.0\.0\.1\\x20-\\x20admin\\x20\\"x \[[^\]]+\] "GET / HTTP/1\.1" 401 - "-" "-"\n$`,
+	)
+
+	if !pattern.MatchString(buf.String()) {
+		t.Errorf("expected an escaped user name on one line, got: %q", buf.String())
+	}
+}
+
+func TestCLFLogger_MissingValues(t *testing.T) {
+	// GIVEN: a CLF logger and an anonymous request without a body in the response
+	var buf bytes.Buffer
+
+	handler := vital.CLFLogger(&buf)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+
+	// WHEN: the request is served
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// THEN: missing values are written as "-"
+	pattern := regexp.MustCompile(`^192\.0\.2\.1 - - \[[^\]]+\] "GET / HTTP/1\.1" 204 - "-" "-"\n$`)
+
+	if !pattern.MatchString(buf.String()) {
+		t.Errorf("expected dashes for missing values, got: %q", buf.String())
+	}
+}
@@ -0,0 +1,93 @@
+package vital
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultConcurrencyRetryAfter is the Retry-After value sent when the concurrency limit is reached.
+const defaultConcurrencyRetryAfter = time.Second
+
+// ConcurrencyOption configures the ConcurrencyLimit middleware.
+type ConcurrencyOption func(*concurrencyConfig)
+
+// concurrencyConfig holds configuration for the ConcurrencyLimit middleware.
+type concurrencyConfig struct {
+	wait       time.Duration
+	retryAfter time.Duration
+}
+
+// WithConcurrencyWait makes requests wait up to d for a free slot before being rejected,
+// instead of being rejected immediately. Waiting also stops when the request context is done.
+func WithConcurrencyWait(d time.Duration) ConcurrencyOption {
+	return func(c *concurrencyConfig) {
+		c.wait = d
+	}
+}
+
+// WithConcurrencyRetryAfter sets the Retry-After duration sent with rejections (default 1s).
+// It is rounded up to whole seconds.
+func WithConcurrencyRetryAfter(d time.Duration) ConcurrencyOption {
+	return func(c *concurrencyConfig) {
+		c.retryAfter = d
+	}
+}
+
+// ConcurrencyLimit returns a middleware that processes at most n requests at a time, for example
+// to protect a downstream that only handles n concurrent calls. When all slots are taken, requests
+// are rejected with a 503 Service Unavailable ProblemDetail and a Retry-After header, either
+// immediately or after waiting (see WithConcurrencyWait). Slots are released even when the
+// handler panics. A limit below 1 is treated as 1.
+func ConcurrencyLimit(n int, opts ...ConcurrencyOption) Middleware {
+	cfg := &concurrencyConfig{
+		retryAfter: defaultConcurrencyRetryAfter,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	slots := make(chan struct{}, max(n, 1))
+	retryAfter := strconv.FormatInt(int64((cfg.retryAfter+time.Second-1)/time.Second), 10)
+
+	return func(next http.Handler) http.Handler {
+		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !acquireSlot(r, slots, cfg.wait) {
+				RespondProblem(w, ServiceUnavailable("too many concurrent requests").
+					WithHeader("Retry-After", retryAfter))
+
+				return
+			}
+
+			defer func() { <-slots }()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// acquireSlot takes a slot, waiting up to wait for one to become free.
+func acquireSlot(r *http.Request, slots chan struct{}, wait time.Duration) bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if wait <= 0 {
+		return false
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-r.Context().Done():
+		return false
+	}
+}
@@ -0,0 +1,121 @@
+package vital_test
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/monkescience/vital"
+)
+
+func TestConcurrencyLimit(t *testing.T) {
+	t.Run("rejects when full", func(t *testing.T) {
+		// GIVEN: a limit of one and a request occupying the slot
+		release := make(chan struct{})
+		started := make(chan struct{})
+
+		handler := vital.ConcurrencyLimit(1, vital.WithConcurrencyRetryAfter(2*time.Second))(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/slow" {
+					close(started)
+					<-release
+				}
+
+				w.WriteHeader(http.StatusOK)
+			}),
+		)
+
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+		}()
+
+		<-started
+
+		// WHEN: another request arrives while the slot is taken
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		// THEN: it is rejected immediately with Retry-After
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+		}
+
+		if rec.Header().Get("Retry-After") != "2" {
+			t.Errorf("expected Retry-After 2, got %q", rec.Header().Get("Retry-After"))
+		}
+
+		close(release)
+		<-done
+
+		// THEN: the slot is free again once the first request finished
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status %d after release, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("blocks until available", func(t *testing.T) {
+		// GIVEN: a limit of one with waiting and a request occupying the slot
+		release := make(chan struct{})
+		started := make(chan struct{})
+
+		handler := vital.ConcurrencyLimit(1, vital.WithConcurrencyWait(5*time.Second))(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/slow" {
+					close(started)
+					<-release
+				}
+
+				w.WriteHeader(http.StatusOK)
+			}),
+		)
+
+		go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+		<-started
+
+		time.AfterFunc(20*time.Millisecond, func() { close(release) })
+
+		// WHEN: another request arrives while the slot is taken
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		// THEN: it waits for the slot and succeeds
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("releases slot on panic", func(t *testing.T) {
+		// GIVEN: a limit of one behind Recovery and a panicking handler
+		limited := vital.ConcurrencyLimit(1)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/panic" {
+				panic("boom")
+			}
+
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		handler := vital.Recovery(slog.New(slog.NewTextHandler(io.Discard, nil)))(limited)
+
+		// WHEN: a request panics and another follows
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		// THEN: the slot was released
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package vital
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultCorrelationHeader = "X-Correlation-ID"
+	defaultAttemptHeader     = "X-Attempt"
+	maxCorrelationIDLength   = 128
+)
+
+// CorrelationIDKey is the context key for the correlation ID shared by a client's retries.
+//
+//nolint:gochecknoglobals // Global key is required for middleware integration
+var CorrelationIDKey = ContextKey{Name: "correlation_id"}
+
+// AttemptKey is the context key for the attempt number sent by a retrying client. Its name is
+// namespaced so it does not share a context slot or log attribute with an application's own
+// "attempt" key.
+//
+//nolint:gochecknoglobals // Global key is required for middleware integration
+var AttemptKey = ContextKey{Name: "correlation_attempt"}
+
+// CorrelationOption configures the Correlation middleware.
+type CorrelationOption func(*correlationConfig)
+
+// correlationConfig holds configuration for the Correlation middleware.
+type correlationConfig struct {
+	header        string
+	attemptHeader string
+}
+
+// WithCorrelationHeader sets the header the correlation ID is read from and echoed in
+// (default "X-Correlation-ID").
+func WithCorrelationHeader(name string) CorrelationOption {
+	return func(c *correlationConfig) {
+		c.header = name
+	}
+}
+
+// WithAttemptHeader sets the header the attempt number is read from (default "X-Attempt").
+func WithAttemptHeader(name string) CorrelationOption {
+	return func(c *correlationConfig) {
+		c.attemptHeader = name
+	}
+}
+
+// Correlation returns a middleware that tracks a client's retries of the same operation.
+// It reads the correlation ID from the correlation header, or generates one when the header
+// is missing or invalid, stores it in the request context under CorrelationIDKey, and echoes
+// it in the response. Unlike a request ID, it stays the same across retries. A positive attempt
+// number from the attempt header is stored under AttemptKey. Both keys are part of BuiltinKeys,
+// so a ContextHandler with WithBuiltinKeys logs them.
+func Correlation(opts ...CorrelationOption) Middleware {
+	cfg := &correlationConfig{
+		header:        defaultCorrelationHeader,
+		attemptHeader: defaultAttemptHeader,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			correlationID := r.Header.Get(cfg.header)
+			if !isValidCorrelationID(correlationID) {
+				correlationID = generateTraceID()
+			}
+
+			w.Header().Set(cfg.header, correlationID)
+
+			ctx := context.WithValue(r.Context(), CorrelationIDKey, correlationID)
+
+			attempt, err := strconv.Atoi(r.Header.Get(cfg.attemptHeader))
+			if err == nil && attempt > 0 {
+				ctx = context.WithValue(ctx, AttemptKey, attempt)
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// isValidCorrelationID reports whether a client-supplied correlation ID is safe to log and echo:
+// non-empty, at most 128 characters, and printable ASCII without spaces.
+func isValidCorrelationID(id string) bool {
+	if id == "" || len(id) > maxCorrelationIDLength {
+		return false
+	}
+
+	for _, c := range []byte(id) {
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetCorrelationID retrieves the correlation ID from the request context.
+func GetCorrelationID(ctx context.Context) string {
+	if correlationID, ok := ctx.Value(CorrelationIDKey).(string); ok {
+		return correlationID
+	}
+
+	return ""
+}
+
+// GetAttempt retrieves the attempt number from the request context, or 0 when none was sent.
+func GetAttempt(ctx context.Context) int {
+	if attempt, ok := ctx.Value(AttemptKey).(int); ok {
+		return attempt
+	}
+
+	return 0
+}
@@ -0,0 +1,127 @@
+package vital_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/monkescience/vital"
+)
+
+func TestCorrelation_PreservedAcrossRetries(t *testing.T) {
+	// GIVEN: a handler logging through a ContextHandler with the builtin keys
+	var buf bytes.Buffer
+
+	logger := slog.New(vital.NewContextHandler(
+		slog.NewJSONHandler(&buf, nil),
+		vital.WithBuiltinKeys(),
+	))
+
+	var seen []string
+
+	handler := vital.Correlation()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, vital.GetCorrelationID(r.Context()))
+		logger.InfoContext(r.Context(), "handling request")
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// WHEN: a client retries the same operation
+	var echoed []string
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.Header.Set("X-Correlation-ID", "order-42")
+		req.Header.Set("X-Attempt", strconv.Itoa(attempt))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		echoed = append(echoed, rec.Header().Get("X-Correlation-ID"))
+	}
+
+	// THEN: both attempts share the correlation ID and are logged with their attempt numbers
+	for i := range 2 {
+		if seen[i] != "order-42" || echoed[i] != "order-42" {
+			t.Errorf("attempt %d: expected correlation ID %q in context and response, got %q and %q",
+				i+1, "order-42", seen[i], echoed[i])
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected two log lines, got: %s", buf.String())
+	}
+
+	for i, line := range lines {
+		var entry struct {
+			CorrelationID string `json:"correlation_id"`
+			Attempt       int    `json:"correlation_attempt"`
+		}
+
+		err := json.Unmarshal([]byte(line), &entry)
+		if err != nil {
+			t.Fatalf("failed to decode log line: %v", err)
+		}
+
+		if entry.CorrelationID != "order-42" || entry.Attempt != i+1 {
+			t.Errorf("expected correlation_id order-42 and correlation_attempt %d, got %+v", i+1, entry)
+		}
+	}
+}
+
+func TestCorrelation_GeneratesID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{name: "missing header", header: ""},
+		{name: "invalid header", header: "bad id\nwith newline"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a request without a usable correlation ID
+			var correlationID string
+
+			var attempt int
+
+			handler := vital.Correlation(
+				vital.WithCorrelationHeader("X-Request-Chain"),
+			)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				correlationID = vital.GetCorrelationID(r.Context())
+				attempt = vital.GetAttempt(r.Context())
+
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Request-Chain", tt.header)
+			}
+
+			rec := httptest.NewRecorder()
+
+			// WHEN: serving the request
+			handler.ServeHTTP(rec, req)
+
+			// THEN: a correlation ID is generated and echoed, and no attempt is set
+			if correlationID == "" || correlationID == tt.header {
+				t.Errorf("expected a generated correlation ID, got %q", correlationID)
+			}
+
+			if got := rec.Header().Get("X-Request-Chain"); got != correlationID {
+				t.Errorf("expected echoed correlation ID %q, got %q", correlationID, got)
+			}
+
+			if attempt != 0 {
+				t.Errorf("expected no attempt, got %d", attempt)
+			}
+		})
+	}
+}
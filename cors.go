@@ -0,0 +1,116 @@
+package vital
+
+import (
+	"net/http"
+	"strings"
+)
+
+// CORSOption configures the CORS middleware.
+type CORSOption func(*corsConfig)
+
+// corsConfig holds configuration for the CORS middleware.
+type corsConfig struct {
+	allowedOrigins map[string]struct{}
+	allowAll       bool
+	originFunc     func(origin string, r *http.Request) bool
+	allowedMethods []string
+	allowedHeaders []string
+}
+
+// WithAllowedOrigins adds origins, such as "https://app.example.com", to the static allowlist.
+// Origins are compared case-insensitively. The origin "*" allows every origin.
+func WithAllowedOrigins(origins ...string) CORSOption {
+	return func(c *corsConfig) {
+		for _, origin := range origins {
+			if origin == "*" {
+				c.allowAll = true
+
+				continue
+			}
+
+			c.allowedOrigins[strings.ToLower(origin)] = struct{}{}
+		}
+	}
+}
+
+// WithOriginFunc validates origins per request, for example against the allowed origins of
+// the request's tenant. It is consulted for origins not in the static allowlist.
+func WithOriginFunc(fn func(origin string, r *http.Request) bool) CORSOption {
+	return func(c *corsConfig) {
+		c.originFunc = fn
+	}
+}
+
+// WithAllowedMethods sets the methods allowed in preflight requests (default GET, HEAD, POST).
+func WithAllowedMethods(methods ...string) CORSOption {
+	return func(c *corsConfig) {
+		c.allowedMethods = methods
+	}
+}
+
+// WithAllowedHeaders sets the request headers allowed in preflight requests (default none).
+func WithAllowedHeaders(headers ...string) CORSOption {
+	return func(c *corsConfig) {
+		c.allowedHeaders = headers
+	}
+}
+
+// CORS returns a middleware that adds Cross-Origin Resource Sharing headers for allowed origins.
+// An allowed origin is reflected in Access-Control-Allow-Origin; requests from other origins are
+// passed on without CORS headers, so the browser blocks the response. Preflight requests from
+// allowed origins are answered with 204 No Content and not passed on.
+func CORS(opts ...CORSOption) Middleware {
+	cfg := &corsConfig{
+		allowedOrigins: make(map[string]struct{}),
+		allowedMethods: []string{http.MethodGet, http.MethodHead, http.MethodPost},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	allowedMethods := strings.Join(cfg.allowedMethods, ", ")
+	allowedHeaders := strings.Join(cfg.allowedHeaders, ", ")
+
+	return func(next http.Handler) http.Handler {
+		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Origin")
+
+			origin := r.Header.Get("Origin")
+			if origin == "" || !cfg.allows(origin, r) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+
+			if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Methods", allowedMethods)
+
+			if allowedHeaders != "" {
+				w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// allows reports whether the origin is in the static allowlist or accepted by the origin function.
+func (c *corsConfig) allows(origin string, r *http.Request) bool {
+	if c.allowAll {
+		return true
+	}
+
+	if _, ok := c.allowedOrigins[strings.ToLower(origin)]; ok {
+		return true
+	}
+
+	return c.originFunc != nil && c.originFunc(origin, r)
+}
@@ -0,0 +1,99 @@
+package vital_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/monkescience/vital"
+)
+
+func TestCORS(t *testing.T) {
+	tenantOrigins := map[string]string{
+		"acme": "https://acme.example.com",
+	}
+
+	originFunc := func(origin string, r *http.Request) bool {
+		return tenantOrigins[r.Header.Get("X-Tenant-ID")] == origin
+	}
+
+	tests := []struct {
+		name           string
+		opts           []vital.CORSOption
+		method         string
+		origin         string
+		expectedOrigin string
+		expectedStatus int
+	}{
+		{
+			name:           "dynamic function allows the tenant origin",
+			opts:           []vital.CORSOption{vital.WithOriginFunc(originFunc)},
+			method:         http.MethodGet,
+			origin:         "https://acme.example.com",
+			expectedOrigin: "https://acme.example.com",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "dynamic function rejects another origin",
+			opts:           []vital.CORSOption{vital.WithOriginFunc(originFunc)},
+			method:         http.MethodGet,
+			origin:         "https://evil.example.com",
+			expectedOrigin: "",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "static allowlist is checked before the function",
+			opts: []vital.CORSOption{
+				vital.WithAllowedOrigins("https://Admin.example.com"),
+				vital.WithOriginFunc(originFunc),
+			},
+			method:         http.MethodGet,
+			origin:         "https://admin.example.com",
+			expectedOrigin: "https://admin.example.com",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "preflight from an allowed origin is answered",
+			opts:           []vital.CORSOption{vital.WithOriginFunc(originFunc)},
+			method:         http.MethodOptions,
+			origin:         "https://acme.example.com",
+			expectedOrigin: "https://acme.example.com",
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a CORS middleware and a request from a tenant's page
+			handler := vital.CORS(tt.opts...)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(tt.method, "/", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("X-Tenant-ID", "acme")
+
+			if tt.method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+			}
+
+			rec := httptest.NewRecorder()
+
+			// WHEN: serving the request
+			handler.ServeHTTP(rec, req)
+
+			// THEN: only allowed origins are reflected
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.expectedOrigin {
+				t.Errorf("expected Access-Control-Allow-Origin %q, got %q", tt.expectedOrigin, got)
+			}
+
+			if got := rec.Header().Get("Vary"); got != "Origin" {
+				t.Errorf("expected Vary Origin, got %q", got)
+			}
+		})
+	}
+}
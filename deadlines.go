@@ -0,0 +1,43 @@
+package vital
+
+import (
+	"net/http"
+	"time"
+)
+
+// PerRequestDeadlines returns a middleware that sets the connection read and write deadlines
+// for each request via http.ResponseController, overriding the server's ReadTimeout and
+// WriteTimeout for routes that need long-lived connections, such as uploads and streams.
+// A positive duration sets the deadline that far from the start of the request, zero removes
+// the deadline (for example a zero write deadline for server-sent events), and a negative
+// duration keeps the server default. Wrapping ResponseWriters must implement Unwrap for the
+// deadlines to apply; otherwise the server defaults stay in effect.
+func PerRequestDeadlines(read, write time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			controller := http.NewResponseController(w)
+			now := time.Now()
+
+			// Errors mean the writer does not support deadlines, so the server defaults apply
+			if read >= 0 {
+				_ = controller.SetReadDeadline(deadlineAfter(now, read))
+			}
+
+			if write >= 0 {
+				_ = controller.SetWriteDeadline(deadlineAfter(now, write))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// deadlineAfter returns now plus d, or the zero time, meaning no deadline, when d is zero.
+func deadlineAfter(now time.Time, d time.Duration) time.Time {
+	if d == 0 {
+		return time.Time{}
+	}
+
+	return now.Add(d)
+}
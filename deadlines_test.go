@@ -0,0 +1,79 @@
+package vital_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/monkescience/vital"
+)
+
+func TestPerRequestDeadlines(t *testing.T) {
+	tests := []struct {
+		name        string
+		middleware  vital.Middleware
+		expectError bool
+	}{
+		{
+			name:       "long write deadline lets a slow handler finish",
+			middleware: vital.PerRequestDeadlines(-1, 2*time.Second),
+		},
+		{
+			name:       "zero write deadline removes the server timeout",
+			middleware: vital.PerRequestDeadlines(-1, 0),
+		},
+		{
+			name:        "server write timeout applies by default",
+			middleware:  vital.PerRequestDeadlines(-1, -1),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a server with a tight write timeout and a handler writing after it passed
+			server := httptest.NewUnstartedServer(tt.middleware(
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					time.Sleep(150 * time.Millisecond)
+
+					_, _ = w.Write([]byte("done"))
+				}),
+			))
+			server.Config.WriteTimeout = 50 * time.Millisecond
+			server.Start()
+			t.Cleanup(server.Close)
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+
+			// WHEN: requesting the slow route
+			resp, err := server.Client().Do(req)
+			if err == nil {
+				defer func() { _ = resp.Body.Close() }()
+
+				_, err = io.ReadAll(resp.Body)
+			}
+
+			// THEN: the response is cut off only without a relaxed write deadline
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected the server write timeout to cut off the response")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected the slow response to complete, got: %v", err)
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("expected status 200, got %d", resp.StatusCode)
+			}
+		})
+	}
+}
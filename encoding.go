@@ -0,0 +1,62 @@
+package vital
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// JSONEncoder encodes the JSON written by RespondProblem, the health handlers, and
+// ProblemDetail.MarshalJSON. Implement it to plug in a faster encoder.
+type JSONEncoder interface {
+	// Marshal returns the JSON encoding of v.
+	Marshal(v any) ([]byte, error)
+	// NewEncoder returns an Encoder that writes JSON values to w.
+	NewEncoder(w io.Writer) Encoder
+}
+
+// Encoder writes JSON values to an output stream.
+type Encoder interface {
+	Encode(v any) error
+}
+
+// stdJSONEncoder is the default JSONEncoder backed by encoding/json.
+type stdJSONEncoder struct{}
+
+// Marshal returns the JSON encoding of v using encoding/json.
+func (stdJSONEncoder) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v) //nolint:wrapcheck // Errors are wrapped by callers
+}
+
+// NewEncoder returns an encoding/json Encoder writing to w.
+func (stdJSONEncoder) NewEncoder(w io.Writer) Encoder {
+	return json.NewEncoder(w)
+}
+
+//nolint:gochecknoglobals // Package-level encoder shared by all response paths
+var (
+	jsonEncoderMu     sync.RWMutex
+	activeJSONEncoder JSONEncoder = stdJSONEncoder{}
+)
+
+// SetJSONEncoder replaces the encoder used for JSON responses. Passing nil restores the
+// default encoding/json encoder. ProblemDetail bodies are built by vital before encoding,
+// so extensions and redaction behave the same with any encoder.
+func SetJSONEncoder(enc JSONEncoder) {
+	if enc == nil {
+		enc = stdJSONEncoder{}
+	}
+
+	jsonEncoderMu.Lock()
+	defer jsonEncoderMu.Unlock()
+
+	activeJSONEncoder = enc
+}
+
+// currentJSONEncoder returns the encoder used for JSON responses.
+func currentJSONEncoder() JSONEncoder {
+	jsonEncoderMu.RLock()
+	defer jsonEncoderMu.RUnlock()
+
+	return activeJSONEncoder
+}
@@ -0,0 +1,89 @@
+package vital_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/monkescience/vital"
+)
+
+// stubJSONEncoder wraps encoding/json and counts how often it is used.
+type stubJSONEncoder struct {
+	marshals atomic.Int32
+	encodes  atomic.Int32
+}
+
+func (s *stubJSONEncoder) Marshal(v any) ([]byte, error) {
+	s.marshals.Add(1)
+
+	return json.Marshal(v)
+}
+
+func (s *stubJSONEncoder) NewEncoder(w io.Writer) vital.Encoder {
+	s.encodes.Add(1)
+
+	return json.NewEncoder(w)
+}
+
+func TestSetJSONEncoder(t *testing.T) {
+	// GIVEN: a stub encoder installed as the JSON encoder
+	stub := &stubJSONEncoder{}
+
+	vital.SetJSONEncoder(stub)
+	t.Cleanup(func() { vital.SetJSONEncoder(nil) })
+
+	// WHEN: responding with a problem and serving a health endpoint
+	problemRec := httptest.NewRecorder()
+	vital.RespondProblem(problemRec, vital.BadRequest("invalid input").
+		WithExtension("Authorization", "Bearer secret"))
+
+	healthRec := httptest.NewRecorder()
+	vital.NewHealthHandler().ServeHTTP(healthRec, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+
+	_, err := json.Marshal(vital.NotFound("missing"))
+	if err != nil {
+		t.Fatalf("failed to marshal problem: %v", err)
+	}
+
+	// THEN: the stub encodes every response and ProblemDetail marshaling
+	if encodes := stub.encodes.Load(); encodes != 2 {
+		t.Errorf("expected 2 encoder uses, got %d", encodes)
+	}
+
+	if marshals := stub.marshals.Load(); marshals != 1 {
+		t.Errorf("expected 1 marshal, got %d", marshals)
+	}
+
+	// THEN: the problem body still carries extensions with sensitive values masked
+	body := problemRec.Body.String()
+	if !strings.Contains(body, `"detail":"invalid input"`) || !strings.Contains(body, `"Authorization":"***"`) {
+		t.Errorf("expected problem body with masked extension, got: %s", body)
+	}
+}
+
+func BenchmarkRespondProblem(b *testing.B) {
+	// Add alternative encoders here to compare them against encoding/json
+	encoders := map[string]vital.JSONEncoder{
+		"encoding/json": nil,
+	}
+
+	for name, encoder := range encoders {
+		b.Run(name, func(b *testing.B) {
+			vital.SetJSONEncoder(encoder)
+			b.Cleanup(func() { vital.SetJSONEncoder(nil) })
+
+			problem := vital.BadRequest("invalid input").WithExtension("field", "email")
+
+			b.ReportAllocs()
+
+			for b.Loop() {
+				vital.RespondProblem(httptest.NewRecorder(), problem)
+			}
+		})
+	}
+}
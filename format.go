@@ -0,0 +1,245 @@
+package vital
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const (
+	// FormatJSON is the response format for JSON bodies, the default.
+	FormatJSON = "json"
+	// FormatXML is the response format for XML bodies.
+	FormatXML = "xml"
+)
+
+// problemXMLNamespace is the XML namespace of problem details defined in RFC 9457 Appendix B.
+const problemXMLNamespace = "urn:ietf:rfc:7807"
+
+// FormatKey is the context key for the negotiated response format.
+//
+//nolint:gochecknoglobals // Global key is required for middleware integration
+var FormatKey = ContextKey{Name: "format"}
+
+// FormatOption configures the NegotiateFormat middleware.
+type FormatOption func(*formatConfig)
+
+// formatConfig holds configuration for the NegotiateFormat middleware.
+type formatConfig struct {
+	queryParameter string
+}
+
+// WithFormatQueryParameter lets the query parameter, such as "format", override the Accept
+// header with "json" or "xml", so the format can be chosen from a browser. Other values are
+// ignored. Disabled by default.
+func WithFormatQueryParameter(name string) FormatOption {
+	return func(c *formatConfig) {
+		c.queryParameter = name
+	}
+}
+
+// NegotiateFormat returns a middleware that negotiates the response format from the Accept
+// header and stores it in the request context under FormatKey. Media ranges are tried in order
+// of their q-values; XML media types select FormatXML, while JSON media types, wildcards, and
+// anything else select FormatJSON. RespondProblemCtx writes problems in the negotiated format.
+func NegotiateFormat(opts ...FormatOption) Middleware {
+	cfg := &formatConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			format := negotiateFormat(r, cfg)
+
+			w.Header().Add("Vary", "Accept")
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), FormatKey, format)))
+		})
+	}
+}
+
+// negotiateFormat returns the format requested by the query parameter override, if enabled
+// and valid, or by the Accept header.
+func negotiateFormat(r *http.Request, cfg *formatConfig) string {
+	if cfg.queryParameter != "" {
+		switch format := strings.ToLower(r.URL.Query().Get(cfg.queryParameter)); format {
+		case FormatJSON, FormatXML:
+			return format
+		}
+	}
+
+	for _, pref := range parseWeightedValues(r.Header.Values("Accept")) {
+		switch strings.ToLower(pref.value) {
+		case "application/problem+xml", "application/xml", "text/xml":
+			return FormatXML
+		case "application/problem+json", "application/json", "application/*", "*/*":
+			return FormatJSON
+		}
+	}
+
+	return FormatJSON
+}
+
+// GetFormat retrieves the format negotiated by the NegotiateFormat middleware from the context.
+// It returns FormatJSON when the middleware did not run.
+func GetFormat(ctx context.Context) string {
+	if format, ok := ctx.Value(FormatKey).(string); ok {
+		return format
+	}
+
+	return FormatJSON
+}
+
+// respondProblemXML writes a ProblemDetail as application/problem+xml as defined in RFC 9457
+// Appendix B. Extensions become elements, arrays list their items as <i> elements, and
+// extensions whose names are not valid XML element names are skipped. Problems that cannot
+// be encoded are written as JSON.
+func respondProblemXML(w http.ResponseWriter, problem *ProblemDetail) {
+	body, err := problemXML(problem)
+	if err != nil {
+		RespondProblem(w, problem)
+
+		return
+	}
+
+	setProblemHeaders(w, problem)
+
+	w.Header().Set("Content-Type", "application/problem+xml")
+	w.WriteHeader(problem.Status)
+	_, _ = w.Write(body)
+}
+
+// problemXML encodes the members of the problem's JSON body as an XML document.
+func problemXML(problem *ProblemDetail) ([]byte, error) {
+	// Round-trip through JSON so extensions of any type become maps, slices, and scalars
+	data, err := json.Marshal(problem.jsonFields())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal problem detail: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var fields map[string]any
+
+	err = decoder.Decode(&fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode problem detail: %w", err)
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString(xml.Header)
+
+	encoder := xml.NewEncoder(&buf)
+
+	root := xml.StartElement{
+		Name: xml.Name{Space: "", Local: "problem"},
+		Attr: []xml.Attr{{Name: xml.Name{Space: "", Local: "xmlns"}, Value: problemXMLNamespace}},
+	}
+
+	err = encoder.EncodeToken(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode problem detail: %w", err)
+	}
+
+	standard := []string{"type", "title", "status", "detail", "instance"}
+	names := append(slices.Clone(standard), slices.Sorted(maps.Keys(fields))...)
+
+	for _, name := range names {
+		value, ok := fields[name]
+		if !ok || !isXMLName(name) {
+			continue
+		}
+
+		delete(fields, name)
+
+		err = encodeXMLValue(encoder, name, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = encoder.EncodeToken(root.End())
+	if err == nil {
+		err = encoder.Flush()
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode problem detail: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// encodeXMLValue encodes a decoded JSON value as an element with the given name.
+func encodeXMLValue(encoder *xml.Encoder, name string, value any) error {
+	start := xml.StartElement{Name: xml.Name{Space: "", Local: name}, Attr: nil}
+
+	err := encoder.EncodeToken(start)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	switch typed := value.(type) {
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(typed)) {
+			if !isXMLName(key) {
+				continue
+			}
+
+			err = encodeXMLValue(encoder, key, typed[key])
+			if err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range typed {
+			err = encodeXMLValue(encoder, "i", item)
+			if err != nil {
+				return err
+			}
+		}
+	case nil:
+	default:
+		err = encoder.EncodeToken(xml.CharData(fmt.Sprint(typed)))
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+	}
+
+	err = encoder.EncodeToken(start.End())
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// isXMLName reports whether name can be used as an XML element name. Only ASCII letters,
+// digits, '_', '-', and '.' are accepted, and names must not start with a digit, '-', '.',
+// or "xml".
+func isXMLName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+
+	for i, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		case i > 0 && (c >= '0' && c <= '9' || c == '-' || c == '.'):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
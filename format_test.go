@@ -0,0 +1,126 @@
+package vital_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/monkescience/vital"
+)
+
+func TestNegotiateFormat_ProblemResponses(t *testing.T) {
+	problemHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vital.RespondProblemCtx(r.Context(), w, vital.UnprocessableEntity("invalid order").
+			WithPointerError("/quantity", "must be positive"))
+	})
+
+	tests := []struct {
+		name                string
+		opts                []vital.FormatOption
+		target              string
+		accept              string
+		expectedContentType string
+	}{
+		{
+			name:                "query override selects XML",
+			opts:                []vital.FormatOption{vital.WithFormatQueryParameter("format")},
+			target:              "/orders?format=xml",
+			accept:              "application/json",
+			expectedContentType: "application/problem+xml",
+		},
+		{
+			name:                "query override is ignored unless enabled",
+			target:              "/orders?format=xml",
+			expectedContentType: "application/problem+json",
+		},
+		{
+			name:                "invalid query override falls back to Accept",
+			opts:                []vital.FormatOption{vital.WithFormatQueryParameter("format")},
+			target:              "/orders?format=yaml",
+			accept:              "text/html;q=0.9, application/xml;q=0.8",
+			expectedContentType: "application/problem+xml",
+		},
+		{
+			name:                "Accept header prefers JSON",
+			target:              "/orders",
+			accept:              "application/xml;q=0.5, application/json",
+			expectedContentType: "application/problem+json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a handler responding with a problem behind format negotiation
+			handler := vital.NegotiateFormat(tt.opts...)(problemHandler)
+
+			req := httptest.NewRequest(http.MethodPost, tt.target, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			rec := httptest.NewRecorder()
+
+			// WHEN: serving the request
+			handler.ServeHTTP(rec, req)
+
+			// THEN: the problem is written in the negotiated format
+			if got := rec.Header().Get("Content-Type"); got != tt.expectedContentType {
+				t.Fatalf("expected content type %q, got %q", tt.expectedContentType, got)
+			}
+
+			if rec.Code != http.StatusUnprocessableEntity {
+				t.Errorf("expected status %d, got %d", http.StatusUnprocessableEntity, rec.Code)
+			}
+
+			if !strings.Contains(rec.Header().Get("Vary"), "Accept") {
+				t.Errorf("expected Vary Accept, got %q", rec.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestNegotiateFormat_ProblemXMLBody(t *testing.T) {
+	// GIVEN: a problem with an extension requested as XML via the query override
+	handler := vital.NegotiateFormat(vital.WithFormatQueryParameter("format"))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			vital.RespondProblemCtx(r.Context(), w, vital.UnprocessableEntity("invalid order").
+				WithPointerError("/quantity", "must be positive"))
+		}),
+	)
+
+	rec := httptest.NewRecorder()
+
+	// WHEN: requesting the problem as XML
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders?format=xml", nil))
+
+	// THEN: the body is an RFC 9457 XML problem with the extension's items as <i> elements
+	var problem struct {
+		XMLName xml.Name `xml:"urn:ietf:rfc:7807 problem"`
+		Title   string   `xml:"title"`
+		Status  int      `xml:"status"`
+		Detail  string   `xml:"detail"`
+		Errors  []struct {
+			Pointer string `xml:"pointer"`
+			Detail  string `xml:"detail"`
+		} `xml:"errors>i"`
+	}
+
+	err := xml.Unmarshal(rec.Body.Bytes(), &problem)
+	if err != nil {
+		t.Fatalf("failed to decode XML problem: %v\n%s", err, rec.Body.String())
+	}
+
+	if problem.Title != "Unprocessable Entity" || problem.Status != http.StatusUnprocessableEntity {
+		t.Errorf("unexpected title or status: %+v", problem)
+	}
+
+	if problem.Detail != "invalid order" {
+		t.Errorf("expected detail %q, got %q", "invalid order", problem.Detail)
+	}
+
+	if len(problem.Errors) != 1 || problem.Errors[0].Pointer != "/quantity" {
+		t.Errorf("expected the pointer error as an item, got: %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,206 @@
+package vital
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ForwardedSource identifies a proxy header used to resolve the client IP and scheme.
+type ForwardedSource int
+
+const (
+	// SourceXForwarded uses the de-facto X-Forwarded-For and X-Forwarded-Proto headers.
+	SourceXForwarded ForwardedSource = iota
+	// SourceForwarded uses the standard RFC 7239 Forwarded header.
+	SourceForwarded
+)
+
+// defaultForwardedSources is the preference order used when no sources are given.
+//
+//nolint:gochecknoglobals // Read-only default preference order
+var defaultForwardedSources = []ForwardedSource{SourceXForwarded, SourceForwarded}
+
+// forwardedElement holds the parameters of the first (client-most) element of a Forwarded header.
+type forwardedElement struct {
+	forAddr string
+	proto   string
+}
+
+// ClientIP resolves the client IP address of the request from proxy headers, consulting the
+// sources in the given preference order (default: X-Forwarded-For, then Forwarded).
+// It falls back to the host part of r.RemoteAddr when no source yields an address.
+// Only trust these headers when the service runs behind a proxy that sets them.
+func ClientIP(r *http.Request, sources ...ForwardedSource) string {
+	if len(sources) == 0 {
+		sources = defaultForwardedSources
+	}
+
+	for _, source := range sources {
+		var ip string
+
+		switch source {
+		case SourceXForwarded:
+			ip = firstListValue(r.Header.Get("X-Forwarded-For"))
+		case SourceForwarded:
+			ip = parseForwarded(r.Header.Get("Forwarded")).forAddr
+		}
+
+		if ip != "" {
+			return ip
+		}
+	}
+
+	return stripPort(r.RemoteAddr)
+}
+
+// Scheme resolves the scheme ("http" or "https") the client used, consulting the sources in the
+// given preference order (default: X-Forwarded-Proto, then Forwarded). It falls back to "https"
+// for TLS connections and "http" otherwise.
+func Scheme(r *http.Request, sources ...ForwardedSource) string {
+	if len(sources) == 0 {
+		sources = defaultForwardedSources
+	}
+
+	for _, source := range sources {
+		var proto string
+
+		switch source {
+		case SourceXForwarded:
+			proto = firstListValue(r.Header.Get("X-Forwarded-Proto"))
+		case SourceForwarded:
+			proto = parseForwarded(r.Header.Get("Forwarded")).proto
+		}
+
+		if proto != "" {
+			return strings.ToLower(proto)
+		}
+	}
+
+	if r.TLS != nil {
+		return "https"
+	}
+
+	return "http"
+}
+
+// parseForwarded parses the first element of an RFC 7239 Forwarded header value.
+// Unknown and obfuscated node identifiers ("unknown", "_hidden") yield an empty address.
+func parseForwarded(value string) forwardedElement {
+	var element forwardedElement
+
+	if value == "" {
+		return element
+	}
+
+	first, _ := splitQuoted(value, ',')
+
+	rest := first
+	for rest != "" {
+		var pair string
+
+		pair, rest = splitQuoted(rest, ';')
+
+		key, val, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			continue
+		}
+
+		val = unquote(strings.TrimSpace(val))
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "for":
+			element.forAddr = forwardedNodeIP(val)
+		case "proto":
+			element.proto = val
+		}
+	}
+
+	return element
+}
+
+// forwardedNodeIP extracts the IP from a Forwarded node such as "192.0.2.60:8080" or "[2001:db8::1]:4711".
+func forwardedNodeIP(node string) string {
+	if node == "" || strings.EqualFold(node, "unknown") || strings.HasPrefix(node, "_") {
+		return ""
+	}
+
+	if strings.HasPrefix(node, "[") {
+		end := strings.IndexByte(node, ']')
+		if end < 0 {
+			return ""
+		}
+
+		return node[1:end]
+	}
+
+	return stripPort(node)
+}
+
+// splitQuoted splits s at the first sep that is not inside a quoted string.
+func splitQuoted(s string, sep byte) (string, string) {
+	quoted := false
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case '\\':
+			if quoted {
+				i++ // skip the escaped character
+			}
+		case sep:
+			if !quoted {
+				return s[:i], s[i+1:]
+			}
+		}
+	}
+
+	return s, ""
+}
+
+// unquote removes surrounding double quotes and backslash escapes from a quoted-string.
+func unquote(s string) string {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s
+	}
+
+	s = s[1 : len(s)-1]
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+
+	escaped := false
+	for i := range len(s) {
+		if s[i] == '\\' && !escaped {
+			escaped = true
+
+			continue
+		}
+
+		escaped = false
+
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// firstListValue returns the first trimmed entry of a comma-separated header value.
+func firstListValue(value string) string {
+	first, _, _ := strings.Cut(value, ",")
+
+	return strings.TrimSpace(first)
+}
+
+// stripPort removes a port from a host:port address, returning the address unchanged otherwise.
+func stripPort(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	return host
+}
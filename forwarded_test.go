@@ -0,0 +1,165 @@
+package vital_test
+
+import (
+	"bytes"
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/monkescience/vital"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		sources    []vital.ForwardedSource
+		expectedIP string
+	}{
+		{
+			name:       "falls back to remote address",
+			expectedIP: "192.0.2.1",
+		},
+		{
+			name:       "X-Forwarded-For first entry",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7, 10.0.0.1"},
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name:       "Forwarded IPv4 with port",
+			headers:    map[string]string{"Forwarded": "for=198.51.100.17:4711;proto=https;by=203.0.113.43"},
+			expectedIP: "198.51.100.17",
+		},
+		{
+			name:       "Forwarded quoted IPv6",
+			headers:    map[string]string{"Forwarded": `For="[2001:db8::1]:4711", for=198.51.100.17`},
+			expectedIP: "2001:db8::1",
+		},
+		{
+			name:       "Forwarded obfuscated node falls back to remote address",
+			headers:    map[string]string{"Forwarded": "for=_hidden"},
+			expectedIP: "192.0.2.1",
+		},
+		{
+			name: "X-Forwarded-For preferred by default",
+			headers: map[string]string{
+				"X-Forwarded-For": "203.0.113.7",
+				"Forwarded":       "for=198.51.100.17",
+			},
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name: "Forwarded preferred when configured",
+			headers: map[string]string{
+				"X-Forwarded-For": "203.0.113.7",
+				"Forwarded":       "for=198.51.100.17",
+			},
+			sources:    []vital.ForwardedSource{vital.SourceForwarded, vital.SourceXForwarded},
+			expectedIP: "198.51.100.17",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a request with the given proxy headers
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			// WHEN: resolving the client IP
+			ip := vital.ClientIP(req, tt.sources...)
+
+			// THEN: the expected address is returned
+			if ip != tt.expectedIP {
+				t.Errorf("expected client IP %q, got %q", tt.expectedIP, ip)
+			}
+		})
+	}
+}
+
+func TestScheme(t *testing.T) {
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		sources        []vital.ForwardedSource
+		tls            bool
+		expectedScheme string
+	}{
+		{
+			name:           "plain connection",
+			expectedScheme: "http",
+		},
+		{
+			name:           "TLS connection",
+			tls:            true,
+			expectedScheme: "https",
+		},
+		{
+			name:           "Forwarded proto",
+			headers:        map[string]string{"Forwarded": `for=198.51.100.17;proto="HTTPS"`},
+			expectedScheme: "https",
+		},
+		{
+			name: "Forwarded preferred when configured",
+			headers: map[string]string{
+				"X-Forwarded-Proto": "http",
+				"Forwarded":         "proto=https",
+			},
+			sources:        []vital.ForwardedSource{vital.SourceForwarded},
+			expectedScheme: "https",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a request with the given proxy headers
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			// WHEN: resolving the scheme
+			scheme := vital.Scheme(req, tt.sources...)
+
+			// THEN: the expected scheme is returned
+			if scheme != tt.expectedScheme {
+				t.Errorf("expected scheme %q, got %q", tt.expectedScheme, scheme)
+			}
+		})
+	}
+}
+
+func TestRequestLogger_ClientIP(t *testing.T) {
+	// GIVEN: a request logger resolving the client IP from the Forwarded header
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	handler := vital.RequestLogger(logger, vital.WithClientIP(vital.SourceForwarded))(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	req.Header.Set("Forwarded", `for="[2001:db8::1]"`)
+
+	// WHEN: the request is served
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// THEN: the client IP from the Forwarded header is logged
+	if !strings.Contains(buf.String(), `"client_ip":"2001:db8::1"`) {
+		t.Errorf("expected client_ip from Forwarded header, got: %s", buf.String())
+	}
+}
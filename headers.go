@@ -0,0 +1,85 @@
+package vital
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// redactedValue replaces the value of sensitive headers wherever headers are logged or rendered.
+const redactedValue = "***"
+
+//nolint:gochecknoglobals // Package-level set shared by all logging and rendering paths
+var (
+	sensitiveHeadersMu sync.RWMutex
+	sensitiveHeaders   = map[string]struct{}{
+		"Authorization":       {},
+		"Cookie":              {},
+		"Set-Cookie":          {},
+		"Proxy-Authorization": {},
+	}
+)
+
+// SetSensitiveHeaders replaces the set of header names whose values are masked as "***"
+// in request logs, panic logs, and problem extensions.
+// By default, Authorization, Cookie, Set-Cookie, and Proxy-Authorization are masked.
+func SetSensitiveHeaders(names ...string) {
+	headers := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		headers[http.CanonicalHeaderKey(name)] = struct{}{}
+	}
+
+	sensitiveHeadersMu.Lock()
+	defer sensitiveHeadersMu.Unlock()
+
+	sensitiveHeaders = headers
+}
+
+// SensitiveHeaders returns the header names whose values are masked.
+func SensitiveHeaders() []string {
+	sensitiveHeadersMu.RLock()
+	defer sensitiveHeadersMu.RUnlock()
+
+	names := make([]string, 0, len(sensitiveHeaders))
+	for name := range sensitiveHeaders {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// isSensitiveHeader reports whether the header value must be masked.
+func isSensitiveHeader(name string) bool {
+	sensitiveHeadersMu.RLock()
+	defer sensitiveHeadersMu.RUnlock()
+
+	_, ok := sensitiveHeaders[http.CanonicalHeaderKey(name)]
+
+	return ok
+}
+
+// redactHeaderValue returns the header value joined for logging, masked if the header is sensitive.
+func redactHeaderValue(name string, values []string) string {
+	if isSensitiveHeader(name) {
+		return redactedValue
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// redactHeaders returns a copy of the headers with sensitive values masked.
+func redactHeaders(header http.Header) http.Header {
+	redacted := make(http.Header, len(header))
+
+	for name, values := range header {
+		if isSensitiveHeader(name) {
+			redacted[name] = []string{redactedValue}
+
+			continue
+		}
+
+		redacted[name] = append([]string(nil), values...)
+	}
+
+	return redacted
+}
@@ -2,8 +2,14 @@ package vital
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"log/slog"
 	"net/http"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -20,7 +26,11 @@ const (
 
 // LiveResponse represents the response payload for the liveness health check endpoint.
 type LiveResponse struct {
-	Status Status `json:"status"`
+	Status      Status `json:"status"`
+	Version     string `json:"version,omitempty"`
+	Environment string `json:"environment,omitempty"`
+	Hostname    string `json:"hostname,omitempty"`
+	PID         int    `json:"pid,omitempty"`
 }
 
 // ReadyResponse represents the response payload for the readiness health check endpoint.
@@ -29,14 +39,33 @@ type ReadyResponse struct {
 	Checks      []CheckResponse `json:"checks"`
 	Version     string          `json:"version,omitempty"`
 	Environment string          `json:"environment,omitempty"`
+	Hostname    string          `json:"hostname,omitempty"`
+	PID         int             `json:"pid,omitempty"`
+}
+
+// ConfigResponse represents the response payload for the configuration summary endpoint.
+// It only carries non-secret settings; checkers are listed by name, and MaintenanceFile only
+// reports whether a maintenance file is configured, not its path.
+type ConfigResponse struct {
+	Version            string   `json:"version,omitempty"`
+	Environment        string   `json:"environment,omitempty"`
+	Checkers           []string `json:"checkers"`
+	ReadyTimeout       string   `json:"ready_timeout"`
+	ReadyTimeoutStatus int      `json:"ready_timeout_status"`
+	MaintenanceFile    bool     `json:"maintenance_file,omitempty"`
+	MaintenanceEnv     string   `json:"maintenance_env,omitempty"`
+	TLS                *bool    `json:"tls,omitempty"`
 }
 
 // CheckResponse represents the result of a single health check.
+// Duration is human-readable, such as "1.5ms"; DurationMS holds the same value in fractional
+// milliseconds for dashboards that aggregate numbers.
 type CheckResponse struct {
-	Name     string `json:"name"`
-	Status   Status `json:"status"`
-	Message  string `json:"message,omitempty"`
-	Duration string `json:"duration,omitempty"`
+	Name       string  `json:"name"`
+	Status     Status  `json:"status"`
+	Message    string  `json:"message,omitempty"`
+	Duration   string  `json:"duration,omitempty"`
+	DurationMS float64 `json:"duration_ms,omitempty"`
 }
 
 // Checker performs a health check and returns a status and optional message.
@@ -45,17 +74,85 @@ type Checker interface {
 	Check(ctx context.Context) (Status, string)
 }
 
+// DefaultTimeoutMessage is the check message reported when a check exceeds its deadline.
+const DefaultTimeoutMessage = "check exceeded deadline"
+
+// defaultOverallReadyTimeout is the default time allowed for all readiness checks to complete.
+const defaultOverallReadyTimeout = 2 * time.Second
+
 type readyConfig struct {
-	overallTimeout time.Duration
+	overallTimeout  time.Duration
+	timeoutMessage  string
+	timeoutStatus   int
+	trustResult     bool
+	logger          *slog.Logger
+	instance        instanceMetadata
+	maintenanceFile string
+	maintenanceEnv  string
+	minCheckBudget  time.Duration
+	slowThreshold   time.Duration
+	cacheHeaders    map[string]string
 }
 
-func runCheck(ctx context.Context, chk Checker) CheckResponse {
+// maintenanceCheckName is the name of the check entry reported while in maintenance mode.
+const maintenanceCheckName = "maintenance"
+
+// maintenanceReason returns why maintenance mode is active, or "" when it is not.
+// The file is stat'd and the environment variable read on every call.
+func (c readyConfig) maintenanceReason() string {
+	if c.maintenanceFile != "" {
+		_, err := os.Stat(c.maintenanceFile)
+		if err == nil {
+			return "maintenance mode enabled by file " + c.maintenanceFile
+		}
+	}
+
+	if c.maintenanceEnv != "" {
+		enabled, err := strconv.ParseBool(os.Getenv(c.maintenanceEnv))
+		if err == nil && enabled {
+			return "maintenance mode enabled by " + c.maintenanceEnv
+		}
+	}
+
+	return ""
+}
+
+// instanceMetadata identifies the process that answered a health probe.
+type instanceMetadata struct {
+	hostname string
+	pid      int
+}
+
+// currentInstanceMetadata returns the hostname and PID of the current process.
+// The hostname is left empty when it cannot be determined.
+func currentInstanceMetadata() instanceMetadata {
+	hostname, _ := os.Hostname()
+
+	return instanceMetadata{hostname: hostname, pid: os.Getpid()}
+}
+
+// runCheck runs a single check and normalizes its result. A panic in the checker is reported
+// as StatusError, so one faulty checker cannot crash the process or hide the other results.
+// It also reports whether the check failed because the context's deadline passed.
+func runCheck(ctx context.Context, chk Checker, cfg readyConfig) (CheckResponse, bool) {
 	start := time.Now()
 
-	status, msg := chk.Check(ctx)
+	status, msg := safeCheck(ctx, chk)
 
 	err := ctx.Err()
-	if err != nil && status == StatusOK {
+	timedOut := status != StatusOK && errors.Is(err, context.DeadlineExceeded)
+
+	if cfg.trustResult {
+		err = nil
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		// Normalize timeouts to a single message regardless of what the checker reported
+		status = StatusError
+		msg = cfg.timeoutMessage
+		timedOut = true
+	case err != nil && status == StatusOK:
 		status = StatusError
 
 		if msg == "" {
@@ -65,27 +162,111 @@ func runCheck(ctx context.Context, chk Checker) CheckResponse {
 		}
 	}
 
+	elapsed := time.Since(start)
+
 	return CheckResponse{
-		Name:     chk.Name(),
-		Status:   status,
-		Message:  msg,
-		Duration: time.Since(start).String(),
-	}
+		Name:       chk.Name(),
+		Status:     status,
+		Message:    msg,
+		Duration:   elapsed.String(),
+		DurationMS: float64(elapsed) / float64(time.Millisecond),
+	}, timedOut
 }
 
 // ReadyOption configures the readiness handler behavior.
 type ReadyOption func(*readyConfig)
 
 // WithOverallReadyTimeout sets the maximum time allowed for all readiness checks to complete.
+// The deadline is computed once per request and shared by all checkers, so time spent by one
+// step counts against every other; an earlier deadline on the request context takes precedence
+// unless it leaves less than the budget set with WithMinCheckBudget.
 func WithOverallReadyTimeout(d time.Duration) ReadyOption {
 	return func(c *readyConfig) { c.overallTimeout = d }
 }
 
+// WithMinCheckBudget sets the minimum time checks get when the request context carries a
+// deadline, so impatient clients do not cause spurious readiness failures. When the request
+// deadline leaves less than d, the checks are detached from the request deadline and
+// cancellation and run with the overall ready timeout, or d if no overall timeout is set.
+// Otherwise the earlier of the request deadline and the overall timeout applies.
+func WithMinCheckBudget(d time.Duration) ReadyOption {
+	return func(c *readyConfig) { c.minCheckBudget = d }
+}
+
+// WithSlowCheckThreshold lists checks that took at least d under "slow_checks" in the warning
+// logged by WithHealthLogger when readiness fails, including checks that passed, so slow
+// dependencies that pushed others past the shared deadline can be identified.
+func WithSlowCheckThreshold(d time.Duration) ReadyOption {
+	return func(c *readyConfig) { c.slowThreshold = d }
+}
+
+// WithReadyTimeoutStatus sets the HTTP status code returned when the overall readiness timeout
+// causes the readiness check to fail, that is when at least one failing check ran out of time
+// (default 503 Service Unavailable). Failures of checks that returned in time use 503.
+// Use 504 Gateway Timeout to distinguish slow checks from failing dependencies.
+func WithReadyTimeoutStatus(code int) ReadyOption {
+	return func(c *readyConfig) { c.timeoutStatus = code }
+}
+
+// WithTrustCheckerResult reports each checker's result as returned, without turning it into
+// StatusError when the context is done by the time the checker returns. Use it when checkers
+// handle their own context and may legitimately succeed right as the deadline passes.
+func WithTrustCheckerResult() ReadyOption {
+	return func(c *readyConfig) { c.trustResult = true }
+}
+
+// WithMaintenanceFile fails readiness with 503 and a "maintenance" check entry while the file
+// at path exists, so touching the file drains the instance from the load balancer.
+// The file is checked on every readiness request; dependency checks still run and are reported.
+func WithMaintenanceFile(path string) ReadyOption {
+	return func(c *readyConfig) { c.maintenanceFile = path }
+}
+
+// WithMaintenanceEnv fails readiness with 503 and a "maintenance" check entry while the named
+// environment variable holds a true value as accepted by strconv.ParseBool ("1", "true", ...).
+// The variable is read on every readiness request.
+func WithMaintenanceEnv(name string) ReadyOption {
+	return func(c *readyConfig) { c.maintenanceEnv = name }
+}
+
+// WithTimeoutMessage sets the check message reported when a check exceeds its deadline.
+// Cancellation of the request context is still reported with the context error.
+func WithTimeoutMessage(msg string) ReadyOption {
+	return func(c *readyConfig) { c.timeoutMessage = msg }
+}
+
 type handlerConfig struct {
-	version     string
-	environment string
-	checkers    []Checker
-	readyOpts   []ReadyOption
+	version                 string
+	environment             string
+	checkers                []Checker
+	readyOpts               []ReadyOption
+	notFoundHandler         http.Handler
+	methodNotAllowedHandler http.Handler
+	logger                  *slog.Logger
+	instanceMetadata        bool
+	livenessMetadata        bool
+	cacheHeaders            map[string]string
+	config                  *configEndpointConfig
+}
+
+// configEndpointConfig configures the configuration summary endpoint.
+type configEndpointConfig struct {
+	auth Middleware
+	tls  *bool
+}
+
+// ConfigEndpointOption configures the configuration summary endpoint.
+type ConfigEndpointOption func(*configEndpointConfig)
+
+// WithConfigAuth protects the configuration summary endpoint with the middleware, such as BasicAuth.
+func WithConfigAuth(auth Middleware) ConfigEndpointOption {
+	return func(c *configEndpointConfig) { c.auth = auth }
+}
+
+// WithConfigTLS reports whether the server serves TLS in the configuration summary.
+// The field is omitted when not set, because the health handler cannot know it.
+func WithConfigTLS(enabled bool) ConfigEndpointOption {
+	return func(c *configEndpointConfig) { c.tls = &enabled }
 }
 
 // HealthHandlerOption configures the health check handler.
@@ -111,6 +292,70 @@ func WithReadyOptions(opts ...ReadyOption) HealthHandlerOption {
 	return func(c *handlerConfig) { c.readyOpts = append(c.readyOpts, opts...) }
 }
 
+// WithNotFoundHandler sets the handler for paths that match no health endpoint.
+// Use this to render a ProblemDetail instead of the default plain-text 404. Unless
+// WithMethodNotAllowedHandler is set as well, health endpoints requested with an unsupported
+// method are answered with a 405 Method Not Allowed ProblemDetail rather than this handler.
+func WithNotFoundHandler(handler http.Handler) HealthHandlerOption {
+	return func(c *handlerConfig) { c.notFoundHandler = handler }
+}
+
+// WithMethodNotAllowedHandler sets the handler for health endpoints requested with an unsupported method.
+// The Allow header is set before the handler is called.
+func WithMethodNotAllowedHandler(handler http.Handler) HealthHandlerOption {
+	return func(c *handlerConfig) { c.methodNotAllowedHandler = handler }
+}
+
+// WithHealthLogger logs a warning with the failing checks whenever the readiness result is not OK.
+// The record is logged with the request context, so a ContextHandler adds the trace context.
+// Configuration warnings, such as a checker without a name, are logged to it as well.
+func WithHealthLogger(logger *slog.Logger) HealthHandlerOption {
+	return func(c *handlerConfig) { c.logger = logger }
+}
+
+// WithInstanceMetadata includes the hostname and PID of the process in liveness and readiness
+// responses, which helps identify the replica that answered a probe behind a load balancer.
+// Both values are read once when the handler is constructed.
+func WithInstanceMetadata() HealthHandlerOption {
+	return func(c *handlerConfig) { c.instanceMetadata = true }
+}
+
+// WithCacheHeaders replaces the cache headers set on health responses, which default to
+// "Cache-Control: no-store, no-cache", "Pragma: no-cache", and an Expires date in the past.
+// Use it for CDNs that mishandle the legacy Pragma and Expires headers, for example with
+// map[string]string{"Cache-Control": "no-store"}. An empty map sets no cache headers.
+func WithCacheHeaders(headers map[string]string) HealthHandlerOption {
+	cacheHeaders := make(map[string]string, len(headers))
+	for name, value := range headers {
+		cacheHeaders[http.CanonicalHeaderKey(name)] = value
+	}
+
+	return func(c *handlerConfig) { c.cacheHeaders = cacheHeaders }
+}
+
+// WithLivenessMetadata includes the version and environment in liveness responses, so a
+// deployment can be verified through the lightweight endpoint. Liveness stays minimal by default.
+func WithLivenessMetadata() HealthHandlerOption {
+	return func(c *handlerConfig) { c.livenessMetadata = true }
+}
+
+// WithConfigEndpoint adds GET /health/config, which reports a summary of the effective
+// non-secret configuration: version, environment, checker names, and readiness settings.
+// Checkers are only listed by name, so credentials in their targets are never exposed.
+// The endpoint is disabled by default and unauthenticated once enabled, so protect it with
+// WithConfigAuth, e.g. WithConfigAuth(BasicAuth("support", password, "config")), unless the
+// health port is only reachable from inside the cluster.
+func WithConfigEndpoint(opts ...ConfigEndpointOption) HealthHandlerOption {
+	return func(c *handlerConfig) {
+		var cfg configEndpointConfig
+		for _, o := range opts {
+			o(&cfg)
+		}
+
+		c.config = &cfg
+	}
+}
+
 // NewHealthHandler creates an HTTP handler that provides health check endpoints at /health/live and /health/ready.
 func NewHealthHandler(opts ...HealthHandlerOption) http.Handler {
 	var handlerCfg handlerConfig
@@ -118,23 +363,128 @@ func NewHealthHandler(opts ...HealthHandlerOption) http.Handler {
 		o(&handlerCfg)
 	}
 
+	readyOpts := handlerCfg.readyOpts
+	if handlerCfg.logger != nil {
+		readyOpts = append(readyOpts, func(c *readyConfig) { c.logger = handlerCfg.logger })
+	}
+
+	if handlerCfg.cacheHeaders != nil {
+		readyOpts = append(readyOpts, func(c *readyConfig) { c.cacheHeaders = handlerCfg.cacheHeaders })
+	}
+
+	var instance instanceMetadata
+	if handlerCfg.instanceMetadata {
+		instance = currentInstanceMetadata()
+		readyOpts = append(readyOpts, func(c *readyConfig) { c.instance = instance })
+	}
+
 	mux := http.NewServeMux()
 
-	mux.HandleFunc("GET /health/live", LiveHandlerFunc())
+	live := LiveResponse{Status: StatusOK, Hostname: instance.hostname, PID: instance.pid}
+	if handlerCfg.livenessMetadata {
+		live.Version = handlerCfg.version
+		live.Environment = handlerCfg.environment
+	}
+
+	mux.HandleFunc("GET /health/live", liveHandlerFunc(live, handlerCfg.cacheHeaders))
 	mux.HandleFunc(
 		"GET /health/ready",
-		ReadyHandlerFunc(handlerCfg.version, handlerCfg.environment, handlerCfg.checkers, handlerCfg.readyOpts...),
+		ReadyHandlerFunc(handlerCfg.version, handlerCfg.environment, handlerCfg.checkers, readyOpts...),
 	)
 
+	if handlerCfg.config != nil {
+		mux.Handle("GET /health/config", configHandler(handlerCfg, readyOpts))
+	}
+
+	methodNotAllowedHandler := handlerCfg.methodNotAllowedHandler
+	if methodNotAllowedHandler == nil && handlerCfg.notFoundHandler != nil {
+		// The catch-all "/" would otherwise answer unsupported methods on health endpoints with 404
+		methodNotAllowedHandler = http.HandlerFunc(respondMethodNotAllowed)
+	}
+
+	if methodNotAllowedHandler != nil {
+		// Method-less patterns are less specific than the GET routes, so they only match other methods
+		methodNotAllowed := allowMethods(methodNotAllowedHandler, http.MethodGet, http.MethodHead)
+		mux.Handle("/health/live", methodNotAllowed)
+		mux.Handle("/health/ready", methodNotAllowed)
+
+		if handlerCfg.config != nil {
+			mux.Handle("/health/config", methodNotAllowed)
+		}
+	}
+
+	if handlerCfg.notFoundHandler != nil {
+		mux.Handle("/", handlerCfg.notFoundHandler)
+	}
+
 	return mux
 }
 
+// configHandler returns the handler for the configuration summary endpoint.
+// The summary is built once, since the configuration does not change after construction.
+func configHandler(handlerCfg handlerConfig, readyOpts []ReadyOption) http.Handler {
+	readyCfg := newReadyConfig(readyOpts)
+	checkers := nonNilCheckers(handlerCfg.checkers)
+
+	names := make([]string, 0, len(checkers))
+
+	for idx, chk := range checkers {
+		name := chk.Name()
+		if name == "" {
+			name = placeholderCheckerName(idx)
+		}
+
+		names = append(names, name)
+	}
+
+	summary := ConfigResponse{
+		Version:            handlerCfg.version,
+		Environment:        handlerCfg.environment,
+		Checkers:           names,
+		ReadyTimeout:       readyCfg.overallTimeout.String(),
+		ReadyTimeoutStatus: readyCfg.timeoutStatus,
+		MaintenanceFile:    readyCfg.maintenanceFile != "",
+		MaintenanceEnv:     readyCfg.maintenanceEnv,
+		TLS:                handlerCfg.config.tls,
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		setCacheHeaders(writer, handlerCfg.cacheHeaders)
+		respondJSON(writer, http.StatusOK, summary)
+	})
+
+	if handlerCfg.config.auth != nil {
+		handler = handlerCfg.config.auth(handler)
+	}
+
+	return handler
+}
+
+// respondMethodNotAllowed writes a 405 Method Not Allowed ProblemDetail.
+func respondMethodNotAllowed(writer http.ResponseWriter, _ *http.Request) {
+	RespondProblem(writer, NewProblemDetail(http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed)))
+}
+
+// allowMethods sets the Allow header to the given methods before calling the handler.
+func allowMethods(handler http.Handler, methods ...string) http.Handler {
+	allow := strings.Join(methods, ", ")
+
+	return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		writer.Header().Set("Allow", allow)
+		handler.ServeHTTP(writer, req)
+	})
+}
+
 // LiveHandlerFunc returns an HTTP handler function for liveness health checks.
 func LiveHandlerFunc() http.HandlerFunc {
-	return func(writer http.ResponseWriter, req *http.Request) {
-		response := LiveResponse{Status: StatusOK}
+	return liveHandlerFunc(LiveResponse{Status: StatusOK}, nil)
+}
 
-		disableResponseCacheHeaders(writer)
+// liveHandlerFunc returns a liveness handler that always responds with the given payload
+// and cache headers (see setCacheHeaders).
+func liveHandlerFunc(response LiveResponse, cacheHeaders map[string]string) http.HandlerFunc {
+	return func(writer http.ResponseWriter, req *http.Request) {
+		setCacheHeaders(writer, cacheHeaders)
 		respondJSON(writer, http.StatusOK, response)
 	}
 }
@@ -147,20 +497,73 @@ func ReadyHandlerFunc(
 	checkers []Checker,
 	opts ...ReadyOption,
 ) http.HandlerFunc {
-	const (
-		defaultOverallTimeout = 2 * time.Second
-	)
+	cfg := newReadyConfig(opts)
 
+	checkers = nonNilCheckers(checkers)
+
+	logger := cfg.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	for idx, chk := range checkers {
+		if chk.Name() == "" {
+			logger.Warn(
+				"health checker has an empty name, reporting it under a placeholder",
+				slog.String("placeholder", placeholderCheckerName(idx)),
+			)
+		}
+	}
+
+	return func(writer http.ResponseWriter, req *http.Request) {
+		readyHandler(writer, req, cfg, version, environment, checkers)
+	}
+}
+
+// newReadyConfig returns the readiness configuration with the options applied to the defaults.
+func newReadyConfig(opts []ReadyOption) readyConfig {
 	cfg := readyConfig{
-		overallTimeout: defaultOverallTimeout,
+		overallTimeout: defaultOverallReadyTimeout,
+		timeoutMessage: DefaultTimeoutMessage,
+		timeoutStatus:  http.StatusServiceUnavailable,
 	}
 
 	for _, o := range opts {
 		o(&cfg)
 	}
 
-	return func(writer http.ResponseWriter, req *http.Request) {
-		readyHandler(writer, req, cfg, version, environment, checkers)
+	return cfg
+}
+
+// nonNilCheckers returns the checkers without nil entries, including typed nil pointers,
+// which commonly result from conditionally constructed checkers.
+func nonNilCheckers(checkers []Checker) []Checker {
+	filtered := make([]Checker, 0, len(checkers))
+
+	for _, chk := range checkers {
+		if isNilChecker(chk) {
+			continue
+		}
+
+		filtered = append(filtered, chk)
+	}
+
+	return filtered
+}
+
+// isNilChecker reports whether the checker is nil or wraps a nil value.
+func isNilChecker(chk Checker) bool {
+	if chk == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(chk)
+
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return value.IsNil()
+	default:
+		return false
 	}
 }
 
@@ -173,31 +576,107 @@ func readyHandler(
 ) {
 	ctx := req.Context()
 
-	ctx, cancel := contextWithTimeoutIfNeeded(ctx, cfg.overallTimeout)
+	if req.Header.Get(dependencyCheckHeader) != "" {
+		ctx = context.WithValue(ctx, dependencyProbeKey{}, true)
+	}
+
+	timeout := cfg.overallTimeout
+
+	if deadline, ok := ctx.Deadline(); ok && cfg.minCheckBudget > 0 && time.Until(deadline) < cfg.minCheckBudget {
+		// The client's deadline is too short for meaningful checks; use the handler's own budget
+		ctx = context.WithoutCancel(ctx)
+
+		if timeout <= 0 {
+			timeout = cfg.minCheckBudget
+		}
+	}
+
+	ctx, cancel := contextWithTimeoutIfNeeded(ctx, timeout)
 	if cancel != nil {
 		defer cancel()
 	}
 
-	checks := runAllChecks(ctx, checkers)
+	checks, timedOut := runAllChecks(ctx, checkers, cfg)
+
+	maintenance := cfg.maintenanceReason()
+	if maintenance != "" {
+		checks = append([]CheckResponse{{
+			Name:    maintenanceCheckName,
+			Status:  StatusError,
+			Message: maintenance,
+		}}, checks...)
+	}
 
 	response := ReadyResponse{
 		Status:      StatusOK,
 		Checks:      checks,
 		Version:     version,
 		Environment: environment,
+		Hostname:    cfg.instance.hostname,
+		PID:         cfg.instance.pid,
 	}
 
 	response.Status = overallStatus(checks)
 
 	statusCode := http.StatusOK
-	if response.Status != StatusOK {
+
+	switch {
+	case response.Status == StatusOK:
+	case maintenance != "":
 		statusCode = http.StatusServiceUnavailable
+	case timedOut:
+		statusCode = cfg.timeoutStatus
+	default:
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	if cfg.logger != nil && response.Status != StatusOK {
+		logFailedChecks(req.Context(), cfg.logger, response, cfg.slowThreshold)
 	}
 
-	disableResponseCacheHeaders(writer)
+	setCacheHeaders(writer, cfg.cacheHeaders)
 	respondJSON(writer, statusCode, response)
 }
 
+// logFailedChecks logs a warning with the overall status, the message and duration of each
+// failing check, and the checks that took at least slowThreshold when it is positive.
+func logFailedChecks(ctx context.Context, logger *slog.Logger, response ReadyResponse, slowThreshold time.Duration) {
+	failing := make([]any, 0, len(response.Checks))
+	durations := make([]any, 0, len(response.Checks))
+
+	var slow []any
+
+	for _, check := range response.Checks {
+		// Entries without a duration, such as the maintenance entry, did not run a checker
+		hasDuration := check.Duration != ""
+		duration := time.Duration(check.DurationMS * float64(time.Millisecond))
+
+		if check.Status != StatusOK {
+			failing = append(failing, slog.String(check.Name, check.Message))
+
+			if hasDuration {
+				durations = append(durations, slog.String(check.Name, check.Duration))
+			}
+		}
+
+		if hasDuration && slowThreshold > 0 && duration >= slowThreshold {
+			slow = append(slow, slog.String(check.Name, check.Duration))
+		}
+	}
+
+	attrs := []slog.Attr{
+		slog.String("status", string(response.Status)),
+		slog.Group("failing_checks", failing...),
+		slog.Group("check_durations", durations...),
+	}
+
+	if len(slow) > 0 {
+		attrs = append(attrs, slog.Group("slow_checks", slow...))
+	}
+
+	logger.LogAttrs(ctx, slog.LevelWarn, "readiness check failed", attrs...)
+}
+
 func contextWithTimeoutIfNeeded(
 	ctx context.Context,
 	duration time.Duration,
@@ -209,8 +688,10 @@ func contextWithTimeoutIfNeeded(
 	return context.WithTimeout(ctx, duration)
 }
 
-func runAllChecks(ctx context.Context, checkers []Checker) []CheckResponse {
+// runAllChecks runs the checkers concurrently and reports whether any failed by running out of time.
+func runAllChecks(ctx context.Context, checkers []Checker, cfg readyConfig) ([]CheckResponse, bool) {
 	responses := make([]CheckResponse, len(checkers))
+	timeouts := make([]bool, len(checkers))
 
 	var waitGroup sync.WaitGroup
 
@@ -218,13 +699,24 @@ func runAllChecks(ctx context.Context, checkers []Checker) []CheckResponse {
 		checkerIndex, chk := idx, checker
 
 		waitGroup.Go(func() {
-			responses[checkerIndex] = runCheck(ctx, chk)
+			response, timedOut := runCheck(ctx, chk, cfg)
+			if response.Name == "" {
+				response.Name = placeholderCheckerName(checkerIndex)
+			}
+
+			responses[checkerIndex] = response
+			timeouts[checkerIndex] = timedOut
 		})
 	}
 
 	waitGroup.Wait()
 
-	return responses
+	return responses, slices.Contains(timeouts, true)
+}
+
+// placeholderCheckerName returns the name reported for a checker whose Name is empty.
+func placeholderCheckerName(index int) string {
+	return "checker_" + strconv.Itoa(index)
 }
 
 func overallStatus(checks []CheckResponse) Status {
@@ -244,12 +736,25 @@ func respondJSON(
 ) {
 	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(statusCode)
-	_ = json.NewEncoder(writer).Encode(payload) //nolint:errchkjson
+	_ = currentJSONEncoder().NewEncoder(writer).Encode(payload)
+}
+
+// setCacheHeaders sets the configured cache headers, or the default no-cache headers when nil.
+func setCacheHeaders(writer http.ResponseWriter, headers map[string]string) {
+	if headers == nil {
+		disableResponseCacheHeaders(writer)
+
+		return
+	}
+
+	for name, value := range headers {
+		writer.Header().Set(name, value)
+	}
 }
 
 // disableResponseCacheHeaders sets headers to prevent caching of health responses.
 func disableResponseCacheHeaders(writer http.ResponseWriter) {
 	writer.Header().Set("Cache-Control", "no-store, no-cache")
 	writer.Header().Set("Pragma", "no-cache")
-	writer.Header().Set("Expires", "Thu, 01 Jan 1970 00:00:00 GMT")
+	writer.Header().Set("Expires", expiredDate)
 }
@@ -0,0 +1,69 @@
+package vital
+
+import (
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// hopByHopHeaders are the hop-by-hop headers defined by RFC 7230 section 6.1, plus the
+// non-standard Proxy-Connection.
+//
+//nolint:gochecknoglobals // Read-only header list
+var hopByHopHeaders = []string{
+	"Connection",
+	"Proxy-Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// StripHopByHop returns a middleware that removes hop-by-hop headers (Connection, Keep-Alive,
+// Transfer-Encoding, Upgrade, etc.) and any headers named in Connection from the request before
+// it reaches the handler. These headers only apply to a single connection, so forwarding or
+// acting on them downstream can confuse handlers and enable request smuggling.
+// Do not use it on routes that accept protocol upgrades such as WebSockets.
+func StripHopByHop() Middleware {
+	return func(next http.Handler) http.Handler {
+		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if hasHopByHopHeaders(r.Header) {
+				r = r.Clone(r.Context())
+				removeHopByHopHeaders(r.Header)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// hasHopByHopHeaders reports whether the header contains any hop-by-hop header.
+func hasHopByHopHeaders(header http.Header) bool {
+	for _, name := range hopByHopHeaders {
+		if _, ok := header[name]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removeHopByHopHeaders deletes the hop-by-hop headers and the headers listed in Connection.
+func removeHopByHopHeaders(header http.Header) {
+	for _, value := range header.Values("Connection") {
+		for name := range strings.SplitSeq(value, ",") {
+			name = textproto.TrimString(name)
+			if name != "" {
+				header.Del(name)
+			}
+		}
+	}
+
+	for _, name := range hopByHopHeaders {
+		header.Del(name)
+	}
+}
@@ -0,0 +1,49 @@
+package vital_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/monkescience/vital"
+)
+
+func TestStripHopByHop(t *testing.T) {
+	// GIVEN: a request carrying hop-by-hop headers and a header named in Connection
+	var received http.Header
+
+	handler := vital.StripHopByHop()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Connection", "keep-alive, X-Internal-Hop")
+	req.Header.Set("Keep-Alive", "timeout=5")
+	req.Header.Set("Transfer-Encoding", "chunked")
+	req.Header.Set("Upgrade", "h2c")
+	req.Header.Set("Te", "trailers")
+	req.Header.Set("X-Internal-Hop", "1")
+	req.Header.Set("X-Request-ID", "abc")
+
+	// WHEN: the request is processed
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// THEN: hop-by-hop headers are absent in the handler
+	for _, name := range []string{"Connection", "Keep-Alive", "Transfer-Encoding", "Upgrade", "Te", "X-Internal-Hop"} {
+		if value := received.Get(name); value != "" {
+			t.Errorf("expected %s to be stripped, got %q", name, value)
+		}
+	}
+
+	// THEN: end-to-end headers are kept
+	if received.Get("X-Request-ID") != "abc" {
+		t.Errorf("expected X-Request-ID to be kept, got %q", received.Get("X-Request-ID"))
+	}
+
+	// THEN: the caller's request is not modified
+	if req.Header.Get("Upgrade") != "h2c" {
+		t.Errorf("expected original request headers to be untouched")
+	}
+}
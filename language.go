@@ -0,0 +1,151 @@
+package vital
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// LanguageKey is the context key for the negotiated language.
+//
+//nolint:gochecknoglobals // Global key is required for middleware integration
+var LanguageKey = ContextKey{Name: "language"}
+
+// weightedValue is a value from a header like Accept or Accept-Language with its q-value.
+type weightedValue struct {
+	value   string
+	quality float64
+}
+
+// Language returns a middleware that negotiates the response language from the Accept-Language
+// header and stores it in the request context under LanguageKey. Language ranges are tried in
+// order of their q-values; a range matches a supported language with the same tag or the same
+// primary subtag, so "en-US" matches "en". The first supported language is used when nothing
+// matches or the header is missing. Supported languages are stored as given.
+func Language(supported ...string) Middleware {
+	if len(supported) == 0 {
+		slog.Warn("no supported languages configured, language negotiation is disabled")
+
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	supported = slices.Clone(supported)
+
+	return func(next http.Handler) http.Handler {
+		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lang := negotiateLanguage(r.Header.Values("Accept-Language"), supported)
+
+			w.Header().Add("Vary", "Accept-Language")
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), LanguageKey, lang)))
+		})
+	}
+}
+
+// negotiateLanguage returns the supported language that best matches the Accept-Language
+// header values, or the first supported language when none matches.
+func negotiateLanguage(header []string, supported []string) string {
+	for _, pref := range parseWeightedValues(header) {
+		if pref.value == "*" {
+			break
+		}
+
+		if lang, ok := matchLanguage(pref.value, supported); ok {
+			return lang
+		}
+	}
+
+	return supported[0]
+}
+
+// parseWeightedValues parses comma-separated header values with q-values, such as
+// Accept-Language language ranges or Accept media ranges, ordered by descending q-value.
+// Values with q=0 or an invalid q-value are dropped.
+func parseWeightedValues(header []string) []weightedValue {
+	var prefs []weightedValue
+
+	for _, value := range header {
+		for part := range strings.SplitSeq(value, ",") {
+			item, params, _ := strings.Cut(part, ";")
+
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+
+			quality, ok := qualityValue(params)
+			if !ok || quality == 0 {
+				continue
+			}
+
+			prefs = append(prefs, weightedValue{value: item, quality: quality})
+		}
+	}
+
+	slices.SortStableFunc(prefs, func(a, b weightedValue) int {
+		return cmp.Compare(b.quality, a.quality)
+	})
+
+	return prefs
+}
+
+// qualityValue parses the q parameter among the parameters of a value, defaulting to 1.
+func qualityValue(params string) (float64, bool) {
+	for param := range strings.SplitSeq(params, ";") {
+		name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if !found || !strings.EqualFold(strings.TrimSpace(name), "q") {
+			continue
+		}
+
+		quality, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || quality < 0 || quality > 1 {
+			return 0, false
+		}
+
+		return quality, true
+	}
+
+	return 1, true
+}
+
+// matchLanguage finds the supported language for a language range, preferring an exact
+// match over a match on the primary subtag.
+func matchLanguage(tag string, supported []string) (string, bool) {
+	for _, lang := range supported {
+		if strings.EqualFold(lang, tag) {
+			return lang, true
+		}
+	}
+
+	primary := primarySubtag(tag)
+
+	for _, lang := range supported {
+		if strings.EqualFold(primarySubtag(lang), primary) {
+			return lang, true
+		}
+	}
+
+	return "", false
+}
+
+// primarySubtag returns the primary language subtag, such as "en" for "en-US".
+func primarySubtag(tag string) string {
+	primary, _, _ := strings.Cut(tag, "-")
+
+	return primary
+}
+
+// GetLanguage retrieves the language negotiated by the Language middleware from the context.
+// It returns an empty string when the middleware did not run.
+func GetLanguage(ctx context.Context) string {
+	if lang, ok := ctx.Value(LanguageKey).(string); ok {
+		return lang
+	}
+
+	return ""
+}
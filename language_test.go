@@ -0,0 +1,86 @@
+package vital_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/monkescience/vital"
+)
+
+func TestLanguage(t *testing.T) {
+	var resolved string
+
+	handler := vital.Language("en", "de", "fr-CA")(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resolved = vital.GetLanguage(r.Context())
+
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expected       string
+	}{
+		{
+			name:           "highest weighted supported language wins",
+			acceptLanguage: "es;q=0.9, de;q=0.7, en;q=0.5",
+			expected:       "de",
+		},
+		{
+			name:           "order does not override q-values",
+			acceptLanguage: "en;q=0.2, fr-CA;q=0.8",
+			expected:       "fr-CA",
+		},
+		{
+			name:           "regional range matches primary language",
+			acceptLanguage: "de-AT, en;q=0.5",
+			expected:       "de",
+		},
+		{
+			name:           "primary range matches regional language",
+			acceptLanguage: "fr",
+			expected:       "fr-CA",
+		},
+		{
+			name:           "excluded language is skipped",
+			acceptLanguage: "de;q=0, fr;q=0.1",
+			expected:       "fr-CA",
+		},
+		{
+			name:           "unsupported languages fall back to the default",
+			acceptLanguage: "ja, zh;q=0.8",
+			expected:       "en",
+		},
+		{
+			name:     "missing header falls back to the default",
+			expected: "en",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a request with the given Accept-Language header
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			rec := httptest.NewRecorder()
+
+			// WHEN: the request is processed
+			handler.ServeHTTP(rec, req)
+
+			// THEN: the best matching supported language is stored in the context
+			if resolved != tt.expected {
+				t.Errorf("expected language %q, got %q", tt.expected, resolved)
+			}
+
+			if rec.Header().Get("Vary") != "Accept-Language" {
+				t.Errorf("expected Vary: Accept-Language, got %q", rec.Header().Get("Vary"))
+			}
+		})
+	}
+}
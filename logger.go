@@ -6,14 +6,38 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"unicode/utf8"
 )
 
 // ContextKey is a strongly-typed key for storing values in context that should be logged.
+//
+// Keys declared as literals are equal when their names are equal, so two packages declaring
+// ContextKey{Name: "user_id"} share one context slot and overwrite each other's values.
+// Use NewContextKey for keys that must not collide. A Registry can only tell keys created by
+// NewContextKey apart: it rejects a second such key with a name that is already registered,
+// while equal literal keys are the same key and their collision is not detected.
 type ContextKey struct {
 	Name string
+
+	// id distinguishes keys created by NewContextKey; it is zero for literal keys
+	id uint64
+}
+
+//nolint:gochecknoglobals // Package-level counter shared by all keys created with NewContextKey
+var contextKeyIDs atomic.Uint64
+
+// NewContextKey returns a ContextKey with a unique identity, so it never matches another key
+// with the same name in a context, even one created by NewContextKey.
+func NewContextKey(name string) ContextKey {
+	return ContextKey{Name: name, id: contextKeyIDs.Add(1)}
 }
 
+// ErrContextKeyCollision is returned when a registry already holds a different key with the same name.
+var ErrContextKeyCollision = errors.New("context key name already registered")
+
 // TraceIDKey is the context key for W3C trace ID.
 //
 //nolint:gochecknoglobals // Global key is required for middleware integration
@@ -29,6 +53,13 @@ var SpanIDKey = ContextKey{Name: "span_id"}
 //nolint:gochecknoglobals // Global key is required for middleware integration
 var TraceFlagsKey = ContextKey{Name: "trace_flags"}
 
+// TraceparentKey is the context key for the full W3C traceparent value, matching the
+// traceparent response header set by TraceContext. It is not part of BuiltinKeys to keep
+// records small; register it with WithContextKeys to log it.
+//
+//nolint:gochecknoglobals // Global key is required for middleware integration
+var TraceparentKey = ContextKey{Name: "traceparent"}
+
 // Registry manages a collection of context keys to extract and log.
 // Each ContextHandler can have its own Registry for isolation.
 type Registry struct {
@@ -44,12 +75,34 @@ func NewRegistry() *Registry {
 	}
 }
 
-// Register adds a context key to this registry.
+// Register adds a context key to this registry. Registering the same key again is a no-op.
+// A different key with a name that is already registered is ignored with a warning logged
+// via slog.Default(); use TryRegister to handle the collision.
 func (r *Registry) Register(key ContextKey) {
+	err := r.TryRegister(key)
+	if err != nil {
+		slog.Warn("ignoring context key", slog.Any("error", err))
+	}
+}
+
+// TryRegister adds a context key to this registry. Registering the same key again is a no-op.
+// A different key with a name that is already registered is rejected with
+// ErrContextKeyCollision, because both would be logged under the same attribute name.
+// Only keys created by NewContextKey can differ while sharing a name; literal keys with the
+// same name are equal, so registering one twice is a no-op rather than a collision.
+func (r *Registry) TryRegister(key ContextKey) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
+	for registered := range r.keys {
+		if registered.Name == key.Name && registered != key {
+			return fmt.Errorf("%w: %q", ErrContextKeyCollision, key.Name)
+		}
+	}
+
 	r.keys[key] = struct{}{}
+
+	return nil
 }
 
 // Keys returns all registered keys as a slice for iteration.
@@ -66,20 +119,27 @@ func (r *Registry) Keys() []ContextKey {
 }
 
 // BuiltinKeys returns all built-in context keys provided by the vital library.
-// These are keys used by vital's middleware (e.g., TraceIDKey, SpanIDKey, TraceFlagsKey).
+// These are keys used by vital's middleware (e.g., TraceIDKey, SpanIDKey, TraceFlagsKey,
+// CorrelationIDKey, AttemptKey).
 func BuiltinKeys() []ContextKey {
 	return []ContextKey{
 		TraceIDKey,
 		SpanIDKey,
 		TraceFlagsKey,
+		CorrelationIDKey,
+		AttemptKey,
 	}
 }
 
 // ContextHandler is a slog.Handler that automatically extracts registered context values
-// and adds them as log attributes.
+// and adds them as log attributes. Values implementing slog.LogValuer are resolved when
+// the record is handled, so a value can control its representation, including rendering
+// as a group (e.g. a User that logs only its ID and role).
 type ContextHandler struct {
-	handler  slog.Handler
-	registry *Registry
+	handler        slog.Handler
+	registry       *Registry
+	traceGroup     string
+	maxValueLength int
 }
 
 // ContextHandlerOption is a functional option for configuring a ContextHandler.
@@ -113,6 +173,24 @@ func WithContextKeys(keys ...ContextKey) ContextHandlerOption {
 	}
 }
 
+// WithTraceGroup emits the registered trace context keys as a nested group with the given
+// name instead of top-level attributes, e.g. "trace": {"id": ..., "span": ..., "flags": ...}.
+// The group is omitted when no trace context is present.
+func WithTraceGroup(name string) ContextHandlerOption {
+	return func(h *ContextHandler) {
+		h.traceGroup = name
+	}
+}
+
+// WithMaxValueLength truncates string and fmt.Stringer context values longer than n characters
+// to n characters followed by "…", protecting log pipelines from pathological values.
+// Numbers, booleans, and other values are left intact. Zero disables truncation.
+func WithMaxValueLength(n int) ContextHandlerOption {
+	return func(h *ContextHandler) {
+		h.maxValueLength = n
+	}
+}
+
 // NewContextHandler creates a new ContextHandler wrapping the provided handler.
 // If the provided handler is already a ContextHandler, it unwraps it first to avoid nesting.
 // Options can be provided to configure which context keys are extracted.
@@ -152,14 +230,33 @@ func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
 
 // Handle processes the log record, extracting registered context values and adding them as attributes.
 func (h *ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	var traceAttrs []slog.Attr
+
 	// Extract all registered context keys and add them to the log record
 	for _, key := range h.registry.Keys() {
-		if value := ctx.Value(key); value != nil {
-			record.AddAttrs(slog.Attr{
-				Key:   key.Name,
-				Value: slog.AnyValue(value),
-			})
+		value := contextValue(ctx, key)
+		if value == nil {
+			continue
+		}
+
+		if name, ok := traceGroupMember(key); ok && h.traceGroup != "" {
+			traceAttrs = append(traceAttrs, slog.Any(name, value))
+
+			continue
 		}
+
+		record.AddAttrs(slog.Attr{
+			Key:   key.Name,
+			Value: h.truncate(slog.AnyValue(value).Resolve()),
+		})
+	}
+
+	if len(traceAttrs) > 0 {
+		slices.SortFunc(traceAttrs, func(a, b slog.Attr) int {
+			return traceGroupOrder[a.Key] - traceGroupOrder[b.Key]
+		})
+
+		record.AddAttrs(slog.Attr{Key: h.traceGroup, Value: slog.GroupValue(traceAttrs...)})
 	}
 
 	err := h.handler.Handle(ctx, record)
@@ -170,12 +267,96 @@ func (h *ContextHandler) Handle(ctx context.Context, record slog.Record) error {
 	return nil
 }
 
+// truncate shortens string and fmt.Stringer values beyond the configured maximum length.
+func (h *ContextHandler) truncate(value slog.Value) slog.Value {
+	if h.maxValueLength <= 0 {
+		return value
+	}
+
+	var text string
+
+	switch value.Kind() {
+	case slog.KindString:
+		text = value.String()
+	case slog.KindAny:
+		stringer, ok := value.Any().(fmt.Stringer)
+		if !ok {
+			return value
+		}
+
+		text = stringer.String()
+	default:
+		return value
+	}
+
+	if utf8.RuneCountInString(text) <= h.maxValueLength {
+		return value
+	}
+
+	runes := []rune(text)
+
+	return slog.StringValue(string(runes[:h.maxValueLength]) + "…")
+}
+
+// traceGroupOrder is the order of members within the trace group.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var traceGroupOrder = map[string]int{"id": 0, "span": 1, "flags": 2, "traceparent": 3}
+
+// traceGroupMember returns the member name of a built-in trace key within the trace group.
+func traceGroupMember(key ContextKey) (string, bool) {
+	switch key {
+	case TraceIDKey:
+		return "id", true
+	case SpanIDKey:
+		return "span", true
+	case TraceFlagsKey:
+		return "flags", true
+	case TraceparentKey:
+		return "traceparent", true
+	default:
+		return "", false
+	}
+}
+
+// contextValue returns the value for a registered key. The built-in trace keys are
+// expanded from the combined trace context entry when present.
+func contextValue(ctx context.Context, key ContextKey) any {
+	traceValue, ok := traceContextValueFrom(ctx)
+	if !ok {
+		return ctx.Value(key)
+	}
+
+	var value string
+
+	switch key {
+	case TraceIDKey:
+		value = traceValue.traceID
+	case SpanIDKey:
+		value = traceValue.spanID
+	case TraceFlagsKey:
+		value = traceValue.traceFlags
+	case TraceparentKey:
+		value = traceValue.traceparent
+	default:
+		return ctx.Value(key)
+	}
+
+	if value == "" {
+		return nil
+	}
+
+	return value
+}
+
 // WithAttrs returns a new handler with the given attributes added.
 // The returned handler preserves the same registry as the original.
 func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	return NewContextHandler(
 		h.handler.WithAttrs(attrs),
 		WithRegistry(h.registry),
+		WithTraceGroup(h.traceGroup),
+		WithMaxValueLength(h.maxValueLength),
 	)
 }
 
@@ -185,6 +366,8 @@ func (h *ContextHandler) WithGroup(name string) slog.Handler {
 	return NewContextHandler(
 		h.handler.WithGroup(name),
 		WithRegistry(h.registry),
+		WithTraceGroup(h.traceGroup),
+		WithMaxValueLength(h.maxValueLength),
 	)
 }
 
@@ -193,6 +376,21 @@ func (h *ContextHandler) Registry() *Registry {
 	return h.registry
 }
 
+// RegisteredKeys returns the sorted names of the context keys this handler extracts.
+// It is safe for concurrent use and intended for debugging and admin views.
+func (h *ContextHandler) RegisteredKeys() []string {
+	keys := h.registry.Keys()
+
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		names = append(names, key.Name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
 // Unwrap returns the underlying handler wrapped by this ContextHandler.
 func (h *ContextHandler) Unwrap() slog.Handler {
 	return h.handler
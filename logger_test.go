@@ -4,10 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/monkescience/vital"
@@ -266,6 +270,148 @@ func TestTraceContext_AutomaticLogging(t *testing.T) {
 	}
 }
 
+func TestTraceContext_TraceparentKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []vital.ContextHandlerOption
+		expectField bool
+	}{
+		{
+			name:        "logged when registered",
+			opts:        []vital.ContextHandlerOption{vital.WithBuiltinKeys(), vital.WithContextKeys(vital.TraceparentKey)},
+			expectField: true,
+		},
+		{
+			name:        "omitted with builtin keys only",
+			opts:        []vital.ContextHandlerOption{vital.WithBuiltinKeys()},
+			expectField: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a context handler with the given keys and trace context middleware
+			var buf bytes.Buffer
+
+			logger := slog.New(vital.NewContextHandler(slog.NewJSONHandler(&buf, nil), tt.opts...))
+
+			testHandler := vital.TraceContext()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				logger.InfoContext(r.Context(), "traced")
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+			rec := httptest.NewRecorder()
+
+			// WHEN: logging within a traced request
+			testHandler.ServeHTTP(rec, req)
+
+			// THEN: the full traceparent matches the response header when registered
+			var logEntry map[string]any
+
+			err := json.Unmarshal(buf.Bytes(), &logEntry)
+			if err != nil {
+				t.Fatalf("failed to parse log output: %v", err)
+			}
+
+			traceparent, ok := logEntry["traceparent"]
+			if ok != tt.expectField {
+				t.Fatalf("expected traceparent present=%v, got: %s", tt.expectField, buf.String())
+			}
+
+			if tt.expectField && traceparent != rec.Header().Get("Traceparent") {
+				t.Errorf("expected traceparent %q to match header %q", traceparent, rec.Header().Get("Traceparent"))
+			}
+		})
+	}
+}
+
+func TestContextHandler_TraceGroup(t *testing.T) {
+	// GIVEN: a context handler emitting trace context as a "trace" group
+	var buf bytes.Buffer
+
+	handler := vital.NewContextHandler(
+		slog.NewJSONHandler(&buf, nil),
+		vital.WithBuiltinKeys(),
+		vital.WithTraceGroup("trace"),
+	)
+	logger := slog.New(handler).With("service", "api")
+
+	testHandler := vital.TraceContext()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		logger.InfoContext(r.Context(), "grouped")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	// WHEN: logging within a traced request
+	testHandler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// THEN: trace values are nested under the group and absent at the top level
+	var logEntry struct {
+		TraceID string `json:"trace_id"`
+		Trace   struct {
+			ID    string `json:"id"`
+			Span  string `json:"span"`
+			Flags string `json:"flags"`
+		} `json:"trace"`
+	}
+
+	err := json.Unmarshal(buf.Bytes(), &logEntry)
+	if err != nil {
+		t.Fatalf("failed to parse log output: %v", err)
+	}
+
+	if logEntry.TraceID != "" {
+		t.Errorf("expected no top-level trace_id, got %q", logEntry.TraceID)
+	}
+
+	if logEntry.Trace.ID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected trace.id to be the incoming trace ID, got %q", logEntry.Trace.ID)
+	}
+
+	if len(logEntry.Trace.Span) != 16 {
+		t.Errorf("expected trace.span to be a span ID, got %q", logEntry.Trace.Span)
+	}
+
+	if logEntry.Trace.Flags != "01" {
+		t.Errorf("expected trace.flags %q, got %q", "01", logEntry.Trace.Flags)
+	}
+}
+
+func TestContextHandler_LegacyTraceKeys(t *testing.T) {
+	// GIVEN: a context with trace values stored directly under the exported keys
+	var buf bytes.Buffer
+
+	logger := slog.New(vital.NewContextHandler(slog.NewJSONHandler(&buf, nil), vital.WithBuiltinKeys()))
+
+	ctx := context.WithValue(context.Background(), vital.TraceIDKey, "4bf92f3577b34da6a3ce929d0e0e4736")
+	ctx = context.WithValue(ctx, vital.SpanIDKey, "00f067aa0ba902b7")
+
+	// WHEN: logging with that context
+	logger.InfoContext(ctx, "legacy")
+
+	// THEN: the values should be logged and readable through the accessors
+	logOutput := buf.String()
+
+	if !strings.Contains(logOutput, `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"`) {
+		t.Errorf("expected trace_id in log, got: %s", logOutput)
+	}
+
+	if !strings.Contains(logOutput, `"span_id":"00f067aa0ba902b7"`) {
+		t.Errorf("expected span_id in log, got: %s", logOutput)
+	}
+
+	if strings.Contains(logOutput, "trace_flags") {
+		t.Errorf("expected no trace_flags in log, got: %s", logOutput)
+	}
+
+	if vital.GetSpanID(ctx) != "00f067aa0ba902b7" {
+		t.Errorf("expected span ID from legacy key, got %q", vital.GetSpanID(ctx))
+	}
+}
+
 func TestRegistry_Register(t *testing.T) {
 	// GIVEN: a new registry
 	registry := vital.NewRegistry()
@@ -292,6 +438,42 @@ func TestRegistry_Register(t *testing.T) {
 	}
 }
 
+func TestRegistry_RegisterCollision(t *testing.T) {
+	// GIVEN: a registry with a key registered by one package
+	registry := vital.NewRegistry()
+
+	billingUserID := vital.NewContextKey("user_id")
+	authUserID := vital.NewContextKey("user_id")
+
+	err := registry.TryRegister(billingUserID)
+	if err != nil {
+		t.Fatalf("expected first registration to succeed, got %v", err)
+	}
+
+	// WHEN: registering the same key again and a different key with the same name
+	sameErr := registry.TryRegister(billingUserID)
+	collisionErr := registry.TryRegister(authUserID)
+
+	// THEN: re-registration is a no-op and the collision is surfaced
+	if sameErr != nil {
+		t.Errorf("expected re-registering the same key to succeed, got %v", sameErr)
+	}
+
+	if !errors.Is(collisionErr, vital.ErrContextKeyCollision) {
+		t.Errorf("expected ErrContextKeyCollision, got %v", collisionErr)
+	}
+
+	if keys := registry.Keys(); len(keys) != 1 || keys[0] != billingUserID {
+		t.Errorf("expected only the first key to be registered, got %v", keys)
+	}
+
+	// THEN: keys created with NewContextKey hold separate context values
+	ctx := context.WithValue(context.Background(), billingUserID, "billing-42")
+	if ctx.Value(authUserID) != nil {
+		t.Error("expected same-named keys from NewContextKey not to share a context value")
+	}
+}
+
 func TestRegistry_Keys(t *testing.T) {
 	// GIVEN: a registry with multiple keys
 	registry := vital.NewRegistry()
@@ -320,15 +502,48 @@ func TestRegistry_Keys(t *testing.T) {
 	}
 }
 
+func TestContextHandler_RegisteredKeys(t *testing.T) {
+	// GIVEN: a context handler with builtin and custom keys
+	handler := vital.NewContextHandler(
+		slog.NewJSONHandler(io.Discard, nil),
+		vital.WithBuiltinKeys(),
+		vital.WithContextKeys(vital.ContextKey{Name: "user_id"}, vital.TenantIDKey),
+	)
+
+	// WHEN: listing the registered keys while registering concurrently
+	var waitGroup sync.WaitGroup
+
+	waitGroup.Go(func() {
+		handler.Registry().Register(vital.ContextKey{Name: "request_id"})
+	})
+
+	_ = handler.RegisteredKeys()
+
+	waitGroup.Wait()
+
+	keys := handler.RegisteredKeys()
+
+	// THEN: all key names are returned in sorted order
+	expected := []string{
+		"correlation_attempt", "correlation_id", "request_id", "span_id", "tenant_id", "trace_flags", "trace_id",
+		"user_id",
+	}
+	if !slices.Equal(keys, expected) {
+		t.Errorf("expected keys %v, got %v", expected, keys)
+	}
+}
+
 func TestBuiltinKeys(t *testing.T) {
 	// WHEN: getting builtin keys
 	keys := vital.BuiltinKeys()
 
-	// THEN: all trace context keys should be included
+	// THEN: all trace context and correlation keys should be included
 	expectedKeys := map[string]bool{
-		"trace_id":    false,
-		"span_id":     false,
-		"trace_flags": false,
+		"trace_id":            false,
+		"span_id":             false,
+		"trace_flags":         false,
+		"correlation_id":      false,
+		"correlation_attempt": false,
 	}
 
 	for _, key := range keys {
@@ -381,6 +596,171 @@ func TestContextHandler_DifferentValueTypes(t *testing.T) {
 	}
 }
 
+// logUser is a context value that controls its log representation.
+type logUser struct {
+	ID       string
+	Email    string
+	Role     string
+	AsGroup  bool
+	Password string
+}
+
+func (u logUser) LogValue() slog.Value {
+	if u.AsGroup {
+		return slog.GroupValue(slog.String("id", u.ID), slog.String("role", u.Role))
+	}
+
+	return slog.StringValue(u.ID)
+}
+
+// recordingHandler captures handled records without resolving their attributes.
+type recordingHandler struct {
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, record slog.Record) error {
+	h.records = append(h.records, record)
+
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+// logStringer is a context value rendered through fmt.Stringer.
+type logStringer string
+
+func (s logStringer) String() string {
+	return string(s)
+}
+
+func TestContextHandler_MaxValueLength(t *testing.T) {
+	payloadKey := vital.ContextKey{Name: "payload"}
+
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{
+			name:     "long string is truncated",
+			value:    strings.Repeat("a", 100),
+			expected: `"payload":"aaaaaaaaaa…"`,
+		},
+		{
+			name:     "long stringer is truncated",
+			value:    logStringer(strings.Repeat("b", 100)),
+			expected: `"payload":"bbbbbbbbbb…"`,
+		},
+		{
+			name:     "short string is kept",
+			value:    "short",
+			expected: `"payload":"short"`,
+		},
+		{
+			name:     "numbers are left intact",
+			value:    12345678901234,
+			expected: `"payload":12345678901234`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a context handler limiting values to 10 characters
+			var buf bytes.Buffer
+
+			logger := slog.New(vital.NewContextHandler(
+				slog.NewJSONHandler(&buf, nil),
+				vital.WithContextKeys(payloadKey),
+				vital.WithMaxValueLength(10),
+			)).With("service", "api")
+
+			ctx := context.WithValue(context.Background(), payloadKey, tt.value)
+
+			// WHEN: logging with that context
+			logger.InfoContext(ctx, "bounded")
+
+			// THEN: only over-long string values are truncated
+			if !strings.Contains(buf.String(), tt.expected) {
+				t.Errorf("expected %s in log, got: %s", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestContextHandler_LogValuer(t *testing.T) {
+	userKey := vital.ContextKey{Name: "user"}
+
+	tests := []struct {
+		name     string
+		user     logUser
+		expected string
+	}{
+		{
+			name:     "scalar representation",
+			user:     logUser{ID: "u-1", Email: "alice@example.com", Password: "secret"},
+			expected: `"user":"u-1"`,
+		},
+		{
+			name:     "group representation",
+			user:     logUser{ID: "u-1", Role: "admin", AsGroup: true, Password: "secret"},
+			expected: `"user":{"id":"u-1","role":"admin"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a context value implementing slog.LogValuer
+			var buf bytes.Buffer
+
+			logger := slog.New(vital.NewContextHandler(slog.NewJSONHandler(&buf, nil), vital.WithContextKeys(userKey)))
+
+			ctx := context.WithValue(context.Background(), userKey, tt.user)
+
+			// WHEN: logging with that context
+			logger.InfoContext(ctx, "resolved")
+
+			// THEN: the value is logged using its LogValue representation
+			if !strings.Contains(buf.String(), tt.expected) {
+				t.Errorf("expected %s in log, got: %s", tt.expected, buf.String())
+			}
+
+			if strings.Contains(buf.String(), "secret") {
+				t.Errorf("expected unexposed fields to be omitted, got: %s", buf.String())
+			}
+		})
+	}
+
+	t.Run("resolved before reaching the wrapped handler", func(t *testing.T) {
+		// GIVEN: a wrapped handler that does not resolve values itself
+		recorder := &recordingHandler{}
+		logger := slog.New(vital.NewContextHandler(recorder, vital.WithContextKeys(userKey)))
+
+		ctx := context.WithValue(context.Background(), userKey, logUser{ID: "u-1", AsGroup: true})
+
+		// WHEN: logging with that context
+		logger.InfoContext(ctx, "resolved")
+
+		// THEN: the wrapped handler receives the resolved group value
+		var kind slog.Kind
+
+		recorder.records[0].Attrs(func(attr slog.Attr) bool {
+			if attr.Key == userKey.Name {
+				kind = attr.Value.Kind()
+			}
+
+			return true
+		})
+
+		if kind != slog.KindGroup {
+			t.Errorf("expected resolved group value, got kind %v", kind)
+		}
+	})
+}
+
 func TestNewHandlerFromConfig(t *testing.T) {
 	t.Run("returns error with empty log level", func(t *testing.T) {
 		// GIVEN: a config with empty level
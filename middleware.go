@@ -1,6 +1,7 @@
 package vital
 
 import (
+	"bufio"
 	"context"
 	"crypto/rand"
 	"crypto/sha256"
@@ -9,15 +10,62 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
 // Middleware is a function that wraps an http.Handler.
 type Middleware func(http.Handler) http.Handler
 
+// Then applies the middleware to the given handler and returns the wrapped handler.
+func (m Middleware) Then(next http.Handler) http.Handler {
+	return m(next)
+}
+
+// Chain composes middleware into one, with the first middleware as the outermost.
+func Chain(middleware ...Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		return applyMiddleware(next, middleware)
+	}
+}
+
+// When returns middleware if cond is true and a pass-through middleware otherwise, so
+// environment-specific middleware fits into a single chain:
+//
+//	vital.Chain(vital.Recovery(logger), vital.When(dev, verboseLogger), vital.TraceContext())
+func When(cond bool, middleware Middleware) Middleware {
+	if !cond {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	return middleware
+}
+
+// DefaultStack returns a middleware composing Recovery, TraceContext, and RequestLogger
+// in the recommended order (outermost to innermost).
+//
+// Recovery is outermost so panics anywhere in the chain are caught, and RequestLogger
+// runs inside TraceContext so access logs carry trace_id and span_id when the logger
+// is backed by a ContextHandler with the built-in keys registered.
+//
+// Example:
+//
+//	handler := vital.DefaultStack(logger).Then(mux)
+func DefaultStack(logger *slog.Logger, opts ...TraceContextOption) Middleware {
+	recovery := Recovery(logger)
+	traceContext := TraceContext(opts...)
+	requestLogger := RequestLogger(logger)
+
+	return func(next http.Handler) http.Handler {
+		return recovery(traceContext(requestLogger(next)))
+	}
+}
+
 // W3C Trace Context constants for validation and defaults.
 const (
 	traceVersion          = "00"
@@ -28,6 +76,7 @@ const (
 	tracestateHeaderName  = "Tracestate"
 	traceFlagSampled      = "01"
 	traceFlagNotSampled   = "00"
+	recentSpanIDCapacity  = 1024
 )
 
 // traceContext represents a W3C Trace Context with traceparent and optional tracestate.
@@ -41,7 +90,7 @@ type traceContext struct {
 
 // FormatTraceparent returns the traceparent header value in W3C format.
 func (tc *traceContext) FormatTraceparent() string {
-	return fmt.Sprintf("%s-%s-%s-%s", tc.Version, tc.TraceID, tc.SpanID, tc.TraceFlags)
+	return tc.Version + "-" + tc.TraceID + "-" + tc.SpanID + "-" + tc.TraceFlags
 }
 
 // BasicAuth returns a middleware that requires HTTP Basic Authentication.
@@ -70,8 +119,8 @@ func BasicAuth(username, password string, realm string) Middleware {
 			passwordMatch := subtle.ConstantTimeCompare(hashedPassword[:], hashedProvidedPassword[:]) == 1
 
 			if !ok || !usernameMatch || !passwordMatch {
-				w.Header().Set("WWW-Authenticate", `Basic realm="`+realm+`"`)
-				RespondProblem(w, Unauthorized("authentication required"))
+				RespondProblem(w, Unauthorized("authentication required").
+					WithHeader("WWW-Authenticate", `Basic realm="`+realm+`"`))
 
 				return
 			}
@@ -81,53 +130,501 @@ func BasicAuth(username, password string, realm string) Middleware {
 	}
 }
 
+// RequestLoggerOption configures the RequestLogger middleware.
+type RequestLoggerOption func(*requestLoggerConfig)
+
+// requestLoggerConfig holds configuration for the RequestLogger middleware.
+type requestLoggerConfig struct {
+	message     string
+	headers     []string
+	startLog    bool
+	contentType bool
+	clientIP    bool
+	ipSources   []ForwardedSource
+	skipPaths   []string
+	sampler     func(*http.Request) bool
+	logID       bool
+	errorSink   bool
+	durationMS  bool
+}
+
+// WithMessage sets the message of the completion record (default "http request").
+// The start record enabled by WithStartLog uses the same message suffixed with " started".
+func WithMessage(message string) RequestLoggerOption {
+	return func(c *requestLoggerConfig) {
+		c.message = message
+	}
+}
+
+// WithLoggedHeaders logs the given request headers under a "headers" group.
+// Values of sensitive headers (see SetSensitiveHeaders) are always masked as "***".
+func WithLoggedHeaders(names ...string) RequestLoggerOption {
+	return func(c *requestLoggerConfig) {
+		c.headers = append(c.headers, names...)
+	}
+}
+
+// WithStartLog emits a debug-level "http request started" record before calling the handler,
+// in addition to the completion record. This helps diagnose requests that never complete.
+func WithStartLog() RequestLoggerOption {
+	return func(c *requestLoggerConfig) {
+		c.startLog = true
+	}
+}
+
+// WithResponseContentType adds the response Content-Type as a "content_type" field.
+func WithResponseContentType() RequestLoggerOption {
+	return func(c *requestLoggerConfig) {
+		c.contentType = true
+	}
+}
+
+// WithClientIP adds the client IP resolved from proxy headers as a "client_ip" field,
+// consulting the sources in the given preference order (see ClientIP).
+func WithClientIP(sources ...ForwardedSource) RequestLoggerOption {
+	return func(c *requestLoggerConfig) {
+		c.clientIP = true
+		c.ipSources = sources
+	}
+}
+
+// WithLogID adds a random 16-hex-character "log_id" to each completion record,
+// so records shipped at-least-once can be deduplicated downstream.
+func WithLogID() RequestLoggerOption {
+	return func(c *requestLoggerConfig) {
+		c.logID = true
+	}
+}
+
+// WithSkipPaths suppresses logging for requests to the given paths, such as health probes.
+// A path ending in "*" matches as a prefix, e.g. "/health/*"; other paths match exactly.
+func WithSkipPaths(paths ...string) RequestLoggerOption {
+	return func(c *requestLoggerConfig) {
+		c.skipPaths = append(c.skipPaths, paths...)
+	}
+}
+
+// WithSampler logs only the requests for which sample returns true.
+// It is consulted before the handler runs, after WithSkipPaths.
+func WithSampler(sample func(r *http.Request) bool) RequestLoggerOption {
+	return func(c *requestLoggerConfig) {
+		c.sampler = sample
+	}
+}
+
+// WithDurationMillis adds the request duration as a numeric "duration_ms" field in fractional
+// milliseconds alongside "duration", for pipelines that aggregate numbers rather than parse
+// duration strings such as "500µs".
+func WithDurationMillis() RequestLoggerOption {
+	return func(c *requestLoggerConfig) {
+		c.durationMS = true
+	}
+}
+
+// WithErrorSink lets handlers attach an error to the request with WithError. The last recorded
+// error is logged as "error" with its Go type as "error_type", so errors a handler handled
+// internally still appear in the request log.
+func WithErrorSink() RequestLoggerOption {
+	return func(c *requestLoggerConfig) {
+		c.errorSink = true
+	}
+}
+
+// errorSinkKey is the context key for the error sink set up by WithErrorSink.
+type errorSinkKey struct{}
+
+// errorSink holds the error recorded for a request.
+type errorSink struct {
+	mu  sync.Mutex
+	err error
+}
+
+// WithError records err on the request so RequestLogger includes it in the request log.
+// It requires the WithErrorSink option and reports whether the error was recorded.
+// Later calls replace earlier errors; a nil error is ignored.
+func WithError(ctx context.Context, err error) bool {
+	sink, ok := ctx.Value(errorSinkKey{}).(*errorSink)
+	if !ok || err == nil {
+		return false
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	sink.err = err
+
+	return true
+}
+
+// recorded returns the error recorded for the request, or nil for a nil sink.
+func (s *errorSink) recorded() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
 // RequestLogger returns a middleware that logs HTTP requests and responses.
 // It logs the method, path, status code, duration, and remote address.
-func RequestLogger(logger *slog.Logger) Middleware {
+func RequestLogger(logger *slog.Logger, opts ...RequestLoggerOption) Middleware {
+	cfg := &requestLoggerConfig{
+		message: "http request",
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	startMessage := cfg.message + " started"
+
 	return func(next http.Handler) http.Handler {
 		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !cfg.shouldLog(r) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
 			start := time.Now()
 
-			// Wrap the ResponseWriter to capture the status code
-			wrapped := &responseWriter{
-				ResponseWriter: w,
-				statusCode:     http.StatusOK,
+			if cfg.startLog {
+				logger.LogAttrs(
+					r.Context(),
+					slog.LevelDebug,
+					startMessage,
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+				)
 			}
 
+			var sink *errorSink
+			if cfg.errorSink {
+				sink = &errorSink{}
+				r = r.WithContext(context.WithValue(r.Context(), errorSinkKey{}, sink))
+			}
+
+			// Wrap the ResponseWriter to capture the status code
+			wrapped, writer := wrapResponseWriter(w)
+
 			// Call the next handler
-			next.ServeHTTP(wrapped, r)
+			next.ServeHTTP(writer, r)
 
 			duration := time.Since(start)
 
-			// Log the request with context (trace context will be added automatically)
-			logger.InfoContext(
-				r.Context(),
-				"http request",
+			attrs := []slog.Attr{
 				slog.String("method", r.Method),
 				slog.String("path", r.URL.Path),
 				slog.Int("status", wrapped.statusCode),
 				slog.Duration("duration", duration),
 				slog.String("remote_addr", r.RemoteAddr),
 				slog.String("user_agent", r.UserAgent()),
-			)
+			}
+
+			if cfg.durationMS {
+				attrs = append(attrs, slog.Float64("duration_ms", float64(duration)/float64(time.Millisecond)))
+			}
+
+			if cfg.contentType {
+				attrs = append(attrs, slog.String("content_type", wrapped.ContentType()))
+			}
+
+			if cfg.clientIP {
+				attrs = append(attrs, slog.String("client_ip", ClientIP(r, cfg.ipSources...)))
+			}
+
+			if cfg.logID {
+				attrs = append(attrs, slog.String("log_id", generateSpanID()))
+			}
+
+			if len(cfg.headers) > 0 {
+				attrs = append(attrs, requestHeadersAttr(r.Header, cfg.headers))
+			}
+
+			if recorded := sink.recorded(); recorded != nil {
+				attrs = append(attrs,
+					slog.String("error", recorded.Error()),
+					slog.String("error_type", fmt.Sprintf("%T", recorded)),
+				)
+			}
+
+			// Log the request with context (trace context will be added automatically)
+			logger.LogAttrs(r.Context(), slog.LevelInfo, cfg.message, attrs...)
 		})
 	}
 }
 
-// responseWriter wraps http.ResponseWriter to capture the status code.
+// shouldLog reports whether the request passes the skip paths and sampler.
+func (c *requestLoggerConfig) shouldLog(r *http.Request) bool {
+	for _, path := range c.skipPaths {
+		prefix, isPrefix := strings.CutSuffix(path, "*")
+		if (isPrefix && strings.HasPrefix(r.URL.Path, prefix)) || path == r.URL.Path {
+			return false
+		}
+	}
+
+	return c.sampler == nil || c.sampler(r)
+}
+
+// requestHeadersAttr builds a "headers" group attribute for the named headers, masking sensitive values.
+func requestHeadersAttr(header http.Header, names []string) slog.Attr {
+	attrs := make([]any, 0, len(names))
+
+	for _, name := range names {
+		values := header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+
+		attrs = append(attrs, slog.String(http.CanonicalHeaderKey(name), redactHeaderValue(name, values)))
+	}
+
+	return slog.Group("headers", attrs...)
+}
+
+// responseWriter wraps http.ResponseWriter to capture the status code and content type.
 type responseWriter struct {
 	http.ResponseWriter
 
-	statusCode int
+	statusCode   int
+	contentType  string
+	wroteHeader  bool
+	bytesWritten int64
+
+	// beforeHeader, if set, is called with the header map just before headers are sent.
+	beforeHeader func(http.Header)
 }
 
 // WriteHeader captures the status code and calls the underlying WriteHeader.
 func (rw *responseWriter) WriteHeader(code int) {
+	rw.captureHeader()
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Write captures the content type on the implicit WriteHeader and writes data.
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	rw.captureHeader()
+
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytesWritten += int64(n)
+
+	return n, err
+}
+
+// Flush sends buffered data to the client, if the underlying writer supports it.
+func (rw *responseWriter) Flush() {
+	rw.captureHeader()
+
+	_ = http.NewResponseController(rw.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// hijack takes over the connection of the underlying writer, which must be an http.Hijacker.
+// The response counts as started, so Recovery does not write a problem afterwards.
+func (rw *responseWriter) hijack() (net.Conn, *bufio.ReadWriter, error) {
+	rw.captureHeader()
+
+	return rw.ResponseWriter.(http.Hijacker).Hijack() //nolint:forcetypeassert,wrapcheck // Only exposed when supported
+}
+
+// readFrom copies src with the underlying writer, which must be an io.ReaderFrom, so
+// sendfile and splice stay available, and counts the bytes written.
+func (rw *responseWriter) readFrom(src io.Reader) (int64, error) {
+	rw.captureHeader()
+
+	n, err := rw.ResponseWriter.(io.ReaderFrom).ReadFrom(src) //nolint:forcetypeassert // Only exposed when supported
+	rw.bytesWritten += n
+
+	return n, err //nolint:wrapcheck // Errors are passed through like Write
+}
+
+// hijackResponseWriter is a responseWriter whose underlying writer is an http.Hijacker.
+type hijackResponseWriter struct{ *responseWriter }
+
+// Hijack takes over the connection of the underlying writer.
+func (w hijackResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) { return w.hijack() }
+
+// readerFromResponseWriter is a responseWriter whose underlying writer is an io.ReaderFrom.
+type readerFromResponseWriter struct{ *responseWriter }
+
+// ReadFrom copies src with the underlying writer.
+func (w readerFromResponseWriter) ReadFrom(src io.Reader) (int64, error) { return w.readFrom(src) }
+
+// hijackReaderFromResponseWriter is a responseWriter whose underlying writer is both an
+// http.Hijacker and an io.ReaderFrom, like the writer of net/http's HTTP/1 server.
+type hijackReaderFromResponseWriter struct{ *responseWriter }
+
+// Hijack takes over the connection of the underlying writer.
+func (w hijackReaderFromResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.hijack()
+}
+
+// ReadFrom copies src with the underlying writer.
+func (w hijackReaderFromResponseWriter) ReadFrom(src io.Reader) (int64, error) {
+	return w.readFrom(src)
+}
+
+// wrapResponseWriter wraps w to capture the status code and returns the capturing writer
+// together with the writer to pass to the next handler. The latter implements http.Hijacker
+// and io.ReaderFrom exactly when w does, so handlers can still assert them directly, for
+// example to upgrade to a websocket.
+func wrapResponseWriter(w http.ResponseWriter) (*responseWriter, http.ResponseWriter) {
+	wrapped := &responseWriter{
+		ResponseWriter: w,
+		statusCode:     http.StatusOK,
+	}
+
+	_, hijacker := w.(http.Hijacker)
+	_, readerFrom := w.(io.ReaderFrom)
+
+	switch {
+	case hijacker && readerFrom:
+		return wrapped, hijackReaderFromResponseWriter{wrapped}
+	case hijacker:
+		return wrapped, hijackResponseWriter{wrapped}
+	case readerFrom:
+		return wrapped, readerFromResponseWriter{wrapped}
+	default:
+		return wrapped, wrapped
+	}
+}
+
+// ContentType returns the response Content-Type as of when headers were sent,
+// or the current header value if nothing has been written yet.
+func (rw *responseWriter) ContentType() string {
+	if !rw.wroteHeader {
+		return rw.Header().Get("Content-Type")
+	}
+
+	return rw.contentType
+}
+
+// captureHeader records the Content-Type the first time headers are sent.
+func (rw *responseWriter) captureHeader() {
+	if rw.wroteHeader {
+		return
+	}
+
+	rw.wroteHeader = true
+
+	if rw.beforeHeader != nil {
+		rw.beforeHeader(rw.Header())
+	}
+
+	rw.contentType = rw.Header().Get("Content-Type")
+}
+
+// TraceContextOption configures the TraceContext middleware.
+type TraceContextOption func(*traceContextConfig)
+
+// traceContextConfig holds configuration for the TraceContext middleware.
+type traceContextConfig struct {
+	rejectSameSpan    bool
+	sameSpanLogger    *slog.Logger
+	traceparentHeader string
+	tracestateHeader  string
+	defaultFlags      string
+}
+
+// WithDefaultTraceFlags sets the trace-flags of traces this service originates (default "01",
+// sampled), e.g. "00" to leave new traces unsampled in cost-sensitive environments. Continued
+// traces keep the incoming flags. It panics with ErrInvalidTraceFlags when flags is not two
+// lowercase hex characters, so a misconfiguration fails at startup.
+func WithDefaultTraceFlags(flags string) TraceContextOption {
+	if len(flags) != traceFlagsLength || !isValidHex(flags) {
+		panic(fmt.Errorf("vital: WithDefaultTraceFlags: %w: %q", ErrInvalidTraceFlags, flags))
+	}
+
+	return func(c *traceContextConfig) {
+		c.defaultFlags = flags
+	}
+}
+
+// ErrInvalidTraceFlags is the panic value of WithDefaultTraceFlags for flags that are not
+// two lowercase hex characters.
+var ErrInvalidTraceFlags = errors.New("invalid default trace flags")
+
+// WithTraceHeaderNames overrides the header names used to read and write the trace context
+// (default "traceparent" and "tracestate"). Empty names keep the default. This is a migration
+// aid for intermediaries that mangle the standard names; prefer the W3C names otherwise.
+func WithTraceHeaderNames(traceparent, tracestate string) TraceContextOption {
+	return func(c *traceContextConfig) {
+		if traceparent != "" {
+			c.traceparentHeader = traceparent
+		}
+
+		if tracestate != "" {
+			c.tracestateHeader = tracestate
+		}
+	}
+}
+
+// WithRejectSameSpan enables a diagnostic guard against reused span-ids.
+// When continuing a trace, the generated child span-id is guaranteed to differ
+// from the incoming parent span-id, and a warning is logged to logger if an incoming
+// parent span-id matches one recently generated by this middleware. A nil logger
+// logs via slog.Default().
+func WithRejectSameSpan(logger *slog.Logger) TraceContextOption {
+	return func(c *traceContextConfig) {
+		c.rejectSameSpan = true
+		c.sameSpanLogger = logger
+	}
+}
+
+// TraceConfig reports the effective configuration of the TraceContext middleware.
+type TraceConfig struct {
+	// DefaultFlags are the trace-flags of traces this service originates.
+	DefaultFlags string `json:"default_flags"`
+	// SampleOriginated reports whether originated traces are marked as sampled.
+	SampleOriginated bool `json:"sample_originated"`
+	// TraceparentHeader is the header the traceparent is read from and written to.
+	TraceparentHeader string `json:"traceparent_header"`
+	// TracestateHeader is the header the tracestate is read from and written to.
+	TracestateHeader string `json:"tracestate_header"`
+	// RejectSameSpan reports whether the reused span-id guard is enabled.
+	RejectSameSpan bool `json:"reject_same_span"`
+}
+
+// ResolveTraceConfig returns the configuration TraceContext uses for the given options, so
+// on-call can confirm whether originated traces are sampled, for example by logging it at
+// startup or serving it from a debug endpoint. Pass the same options as to TraceContext.
+func ResolveTraceConfig(opts ...TraceContextOption) TraceConfig {
+	cfg := newTraceContextConfig(opts)
+
+	// Default flags are validated as two hex characters; bit 0 is the W3C sampled flag
+	flags, _ := hex.DecodeString(cfg.defaultFlags)
+
+	return TraceConfig{
+		DefaultFlags:      cfg.defaultFlags,
+		SampleOriginated:  len(flags) == 1 && flags[0]&1 == 1,
+		TraceparentHeader: cfg.traceparentHeader,
+		TracestateHeader:  cfg.tracestateHeader,
+		RejectSameSpan:    cfg.rejectSameSpan,
+	}
+}
+
+// newTraceContextConfig returns the TraceContext configuration with the options applied to the defaults.
+func newTraceContextConfig(opts []TraceContextOption) *traceContextConfig {
+	cfg := &traceContextConfig{
+		traceparentHeader: traceparentHeaderName,
+		tracestateHeader:  tracestateHeaderName,
+		defaultFlags:      traceFlagSampled,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
 // Deprecated: Use OTel() middleware instead for W3C trace propagation with full observability.
 //
 // TraceContext returns a middleware that implements W3C Trace Context propagation.
@@ -139,13 +636,24 @@ func (rw *responseWriter) WriteHeader(code int) {
 //   - If no/invalid traceparent: Generate new trace-id and span-id
 //   - Always sets traceparent and tracestate (if present) in response headers
 //   - Adds trace_id, span_id, trace_flags to request context for logging
-func TraceContext() Middleware {
+func TraceContext(opts ...TraceContextOption) Middleware {
+	cfg := newTraceContextConfig(opts)
+
+	var recent *recentSpanIDs
+	if cfg.rejectSameSpan {
+		recent = newRecentSpanIDs(recentSpanIDCapacity)
+	}
+
+	sameSpanLogger := cfg.sameSpanLogger
+	if sameSpanLogger == nil {
+		sameSpanLogger = slog.Default()
+	}
+
 	return func(next http.Handler) http.Handler {
 		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Parse incoming trace context from headers
-			traceparent := r.Header.Get(traceparentHeaderName)
-			tracestate := r.Header.Get(tracestateHeaderName)
+			traceparent := r.Header.Get(cfg.traceparentHeader)
 
 			var tc *traceContext
 
@@ -159,28 +667,49 @@ func TraceContext() Middleware {
 						TraceID:    parsed.TraceID,
 						SpanID:     generateSpanID(),
 						TraceFlags: parsed.TraceFlags,
-						TraceState: tracestate,
+						// Tracestate is only meaningful alongside a valid traceparent
+						TraceState: r.Header.Get(cfg.tracestateHeader),
+					}
+
+					if recent != nil {
+						if recent.contains(parsed.SpanID) {
+							sameSpanLogger.WarnContext(
+								r.Context(),
+								"incoming span-id matches a recently generated span-id",
+								slog.String("trace_id", parsed.TraceID),
+								slog.String("span_id", parsed.SpanID),
+							)
+						}
+
+						tc.SpanID = generateChildSpanID(parsed.SpanID)
 					}
 				}
 			}
 
 			if tc == nil {
 				// No valid traceparent: generate new trace
-				tc = generateTraceContext()
+				tc = generateTraceContext(cfg.defaultFlags)
+			}
+
+			if recent != nil {
+				recent.add(tc.SpanID)
 			}
 
-			// Add trace context to request context
-			ctx := r.Context()
-			ctx = context.WithValue(ctx, TraceIDKey, tc.TraceID)
-			ctx = context.WithValue(ctx, SpanIDKey, tc.SpanID)
-			ctx = context.WithValue(ctx, TraceFlagsKey, tc.TraceFlags)
-			r = r.WithContext(ctx)
+			formatted := tc.FormatTraceparent()
+
+			// Add trace context to request context as a single entry
+			r = r.WithContext(withTraceContextValue(r.Context(), traceContextValue{
+				traceID:     tc.TraceID,
+				spanID:      tc.SpanID,
+				traceFlags:  tc.TraceFlags,
+				traceparent: formatted,
+			}))
 
 			// Set response headers
-			w.Header().Set(traceparentHeaderName, tc.FormatTraceparent())
+			w.Header().Set(cfg.traceparentHeader, formatted)
 
 			if tc.TraceState != "" {
-				w.Header().Set(tracestateHeaderName, tc.TraceState)
+				w.Header().Set(cfg.tracestateHeader, tc.TraceState)
 			}
 
 			next.ServeHTTP(w, r)
@@ -266,12 +795,12 @@ func isValidHex(s string) bool {
 }
 
 // generateTraceContext generates a new W3C Trace Context with random trace-id and span-id.
-func generateTraceContext() *traceContext {
+func generateTraceContext(flags string) *traceContext {
 	return &traceContext{
 		Version:    traceVersion,
 		TraceID:    generateTraceID(),
 		SpanID:     generateSpanID(),
-		TraceFlags: traceFlagSampled,
+		TraceFlags: flags,
 		TraceState: "",
 	}
 }
@@ -315,8 +844,88 @@ func generateSpanID() string {
 	return hex.EncodeToString(bytes)
 }
 
+// generateChildSpanID generates a span ID that is guaranteed to differ from the parent span ID.
+func generateChildSpanID(parentSpanID string) string {
+	for {
+		spanID := generateSpanID()
+		if spanID != parentSpanID {
+			return spanID
+		}
+	}
+}
+
+// recentSpanIDs is a bounded set of recently generated span IDs.
+// Once full, the oldest span ID is evicted for each new one added.
+type recentSpanIDs struct {
+	mu    sync.Mutex
+	ring  []string
+	index map[string]struct{}
+	next  int
+}
+
+// newRecentSpanIDs creates a recentSpanIDs set holding at most capacity entries.
+func newRecentSpanIDs(capacity int) *recentSpanIDs {
+	return &recentSpanIDs{
+		ring:  make([]string, capacity),
+		index: make(map[string]struct{}, capacity),
+	}
+}
+
+// add records a span ID, evicting the oldest entry when the set is full.
+func (s *recentSpanIDs) add(spanID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if evicted := s.ring[s.next]; evicted != "" {
+		delete(s.index, evicted)
+	}
+
+	s.ring[s.next] = spanID
+	s.index[spanID] = struct{}{}
+	s.next = (s.next + 1) % len(s.ring)
+}
+
+// contains reports whether the span ID was recently generated.
+func (s *recentSpanIDs) contains(spanID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.index[spanID]
+
+	return ok
+}
+
+// traceContextKey is the context key for the combined trace context entry.
+type traceContextKey struct{}
+
+// traceContextValue holds trace_id, span_id, trace_flags, and the formatted traceparent in a
+// single context entry, avoiding one context allocation and lookup per field.
+type traceContextValue struct {
+	traceID     string
+	spanID      string
+	traceFlags  string
+	traceparent string
+}
+
+// withTraceContextValue returns a copy of ctx carrying the combined trace context entry.
+func withTraceContextValue(ctx context.Context, value traceContextValue) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, value)
+}
+
+// traceContextValueFrom retrieves the combined trace context entry from ctx.
+func traceContextValueFrom(ctx context.Context) (traceContextValue, bool) {
+	value, ok := ctx.Value(traceContextKey{}).(traceContextValue)
+
+	return value, ok
+}
+
 // GetTraceID retrieves the trace ID from the request context.
+// Values stored directly under TraceIDKey are still honored for backward compatibility.
 func GetTraceID(ctx context.Context) string {
+	if value, ok := traceContextValueFrom(ctx); ok {
+		return value.traceID
+	}
+
 	if traceID, ok := ctx.Value(TraceIDKey).(string); ok {
 		return traceID
 	}
@@ -325,7 +934,12 @@ func GetTraceID(ctx context.Context) string {
 }
 
 // GetSpanID retrieves the span ID from the request context.
+// Values stored directly under SpanIDKey are still honored for backward compatibility.
 func GetSpanID(ctx context.Context) string {
+	if value, ok := traceContextValueFrom(ctx); ok {
+		return value.spanID
+	}
+
 	if spanID, ok := ctx.Value(SpanIDKey).(string); ok {
 		return spanID
 	}
@@ -334,7 +948,12 @@ func GetSpanID(ctx context.Context) string {
 }
 
 // GetTraceFlags retrieves the trace flags from the request context.
+// Values stored directly under TraceFlagsKey are still honored for backward compatibility.
 func GetTraceFlags(ctx context.Context) string {
+	if value, ok := traceContextValueFrom(ctx); ok {
+		return value.traceFlags
+	}
+
 	if traceFlags, ok := ctx.Value(TraceFlagsKey).(string); ok {
 		return traceFlags
 	}
@@ -343,24 +962,38 @@ func GetTraceFlags(ctx context.Context) string {
 }
 
 // Recovery returns a middleware that recovers from panics and returns a 500 error.
+// If the handler already started the response (for example a streaming handler that
+// panics mid-stream), no problem body is written; the panic is logged and the
+// connection is aborted via http.ErrAbortHandler so the client sees a truncated response.
 func Recovery(logger *slog.Logger) Middleware {
 	return func(next http.Handler) http.Handler {
 		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			wrapped, writer := wrapResponseWriter(w)
+
 			defer func() {
 				if err := recover(); err != nil {
+					if header, ok := err.(http.Header); ok {
+						err = redactHeaders(header)
+					}
+
 					logger.Error(
 						"panic recovered",
 						slog.Any("error", err),
 						slog.String("method", r.Method),
 						slog.String("path", r.URL.Path),
+						slog.Bool("response_started", wrapped.wroteHeader),
 					)
 
+					if wrapped.wroteHeader {
+						panic(http.ErrAbortHandler)
+					}
+
 					RespondProblem(w, InternalServerError("internal server error"))
 				}
 			}()
 
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(writer, r)
 		})
 	}
 }
@@ -4,14 +4,22 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"log"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/monkescience/vital"
+	"github.com/monkescience/vital/vitaltest"
 )
 
 func TestBasicAuth(t *testing.T) {
@@ -82,8 +90,10 @@ func TestBasicAuth(t *testing.T) {
 			// WHEN: the protected handler processes the request
 			protectedHandler.ServeHTTP(rec, req)
 
-			// THEN: it should return the expected status and headers
-			if rec.Code != tt.expectedStatus {
+			// THEN: it should return the expected status, problem body, and headers
+			if tt.expectAuth {
+				vitaltest.AssertProblem(t, rec, http.StatusUnauthorized, "Unauthorized")
+			} else if rec.Code != tt.expectedStatus {
 				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
 			}
 
@@ -114,7 +124,9 @@ func TestBasicAuth_DefaultRealm(t *testing.T) {
 	// WHEN: accessing without credentials
 	protectedHandler.ServeHTTP(rec, req)
 
-	// THEN: it should use the default realm "Restricted"
+	// THEN: it should reject the request with a problem and use the default realm "Restricted"
+	vitaltest.AssertProblem(t, rec, http.StatusUnauthorized, "Unauthorized")
+
 	authHeader := rec.Header().Get("WWW-Authenticate")
 	if !strings.Contains(authHeader, "Restricted") {
 		t.Errorf("expected default realm 'Restricted', got %q", authHeader)
@@ -164,6 +176,140 @@ func TestRequestLogger(t *testing.T) {
 	}
 }
 
+func TestRequestLogger_WithStartLog(t *testing.T) {
+	// GIVEN: a debug-level logger and request logger with start logging enabled
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	handler := vital.RequestLogger(logger, vital.WithStartLog())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+
+	// WHEN: the handler processes the request
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// THEN: a start record and a completion record should be emitted
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log records, got %d: %s", len(lines), buf.String())
+	}
+
+	var started, completed map[string]any
+
+	err := json.Unmarshal([]byte(lines[0]), &started)
+	if err != nil {
+		t.Fatalf("failed to parse start record: %v", err)
+	}
+
+	err = json.Unmarshal([]byte(lines[1]), &completed)
+	if err != nil {
+		t.Fatalf("failed to parse completion record: %v", err)
+	}
+
+	if started["msg"] != "http request started" || started["level"] != "DEBUG" {
+		t.Errorf("expected debug start record, got %v", started)
+	}
+
+	if started["path"] != "/api/users" {
+		t.Errorf("expected path in start record, got %v", started["path"])
+	}
+
+	if completed["msg"] != "http request" {
+		t.Errorf("expected completion record, got %v", completed)
+	}
+}
+
+func TestRequestLogger_WithResponseContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     http.HandlerFunc
+		contentType string
+	}{
+		{
+			name: "explicit WriteHeader",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+			},
+			contentType: "application/json",
+		},
+		{
+			name: "implicit WriteHeader",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+				_, _ = w.Write([]byte("ok"))
+			},
+			contentType: "text/plain; charset=utf-8",
+		},
+		{
+			name: "problem response",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				vital.RespondProblem(w, vital.NotFound("missing"))
+			},
+			contentType: "application/problem+json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a request logger with content type logging enabled
+			var buf bytes.Buffer
+
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			handler := vital.RequestLogger(logger, vital.WithResponseContentType())(tt.handler)
+
+			// WHEN: the handler processes the request
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			// THEN: the logged content type should match what the handler set
+			var entry map[string]any
+
+			err := json.Unmarshal(buf.Bytes(), &entry)
+			if err != nil {
+				t.Fatalf("failed to parse log output: %v", err)
+			}
+
+			if entry["content_type"] != tt.contentType {
+				t.Errorf("expected content_type %q, got %v", tt.contentType, entry["content_type"])
+			}
+		})
+	}
+}
+
+func TestRequestLogger_WithMessage(t *testing.T) {
+	// GIVEN: a request logger with a custom message and start logging
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	handler := vital.RequestLogger(logger, vital.WithMessage("access"), vital.WithStartLog())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+
+	// WHEN: the handler processes the request
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// THEN: both records should use the custom message
+	logOutput := buf.String()
+
+	if !strings.Contains(logOutput, `"msg":"access started"`) {
+		t.Errorf("expected start record with custom message, got: %s", logOutput)
+	}
+
+	if !strings.Contains(logOutput, `"msg":"access"`) {
+		t.Errorf("expected completion record with custom message, got: %s", logOutput)
+	}
+
+	if strings.Contains(logOutput, "http request") {
+		t.Errorf("expected default message to be replaced, got: %s", logOutput)
+	}
+}
+
 func TestRequestLogger_CapturesStatusCode(t *testing.T) {
 	var buf bytes.Buffer
 
@@ -210,6 +356,311 @@ func TestRequestLogger_CapturesStatusCode(t *testing.T) {
 	}
 }
 
+func TestRequestLogger_SensitiveHeaders(t *testing.T) {
+	t.Run("masks default sensitive headers", func(t *testing.T) {
+		// GIVEN: a request logger configured to log the Authorization header
+		var buf bytes.Buffer
+
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+		handler := vital.RequestLogger(logger, vital.WithLoggedHeaders("Authorization", "Cookie", "X-Request-Id"))(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}),
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer secret-token")
+		req.Header.Set("Cookie", "session=abc123")
+		req.Header.Set("X-Request-Id", "req-1")
+
+		// WHEN: the handler processes the request
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		// THEN: sensitive values should be masked and others logged
+		logOutput := buf.String()
+
+		if strings.Contains(logOutput, "secret-token") || strings.Contains(logOutput, "abc123") {
+			t.Errorf("expected sensitive header values to be masked, got: %s", logOutput)
+		}
+
+		for _, field := range []string{`"Authorization":"***"`, `"Cookie":"***"`, `"X-Request-Id":"req-1"`} {
+			if !strings.Contains(logOutput, field) {
+				t.Errorf("expected log to contain %q, got: %s", field, logOutput)
+			}
+		}
+	})
+
+	t.Run("respects overridden sensitive headers", func(t *testing.T) {
+		// GIVEN: a custom sensitive header set
+		previous := vital.SensitiveHeaders()
+		vital.SetSensitiveHeaders("Authorization", "X-Api-Key")
+		t.Cleanup(func() { vital.SetSensitiveHeaders(previous...) })
+
+		var buf bytes.Buffer
+
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+		handler := vital.RequestLogger(logger, vital.WithLoggedHeaders("X-Api-Key"))(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Api-Key", "key-123")
+
+		// WHEN: the handler processes the request
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		// THEN: the custom header should be masked
+		if !strings.Contains(buf.String(), `"X-Api-Key":"***"`) {
+			t.Errorf("expected X-Api-Key to be masked, got: %s", buf.String())
+		}
+	})
+}
+
+func TestRequestLogger_ErrorSink(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []vital.RequestLoggerOption
+		expectError  bool
+		expectStored bool
+	}{
+		{
+			name:         "recorded error is logged",
+			opts:         []vital.RequestLoggerOption{vital.WithErrorSink()},
+			expectError:  true,
+			expectStored: true,
+		},
+		{
+			name:         "ignored without the option",
+			expectError:  false,
+			expectStored: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a handler that handles an error internally and records it
+			var buf bytes.Buffer
+
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+			var stored bool
+
+			handler := vital.RequestLogger(logger, tt.opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				stored = vital.WithError(r.Context(), fs.ErrNotExist)
+
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			// WHEN: the request is served
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			// THEN: the access log reflects the recorded error
+			var entry map[string]any
+
+			err := json.Unmarshal(buf.Bytes(), &entry)
+			if err != nil {
+				t.Fatalf("failed to parse log output: %v", err)
+			}
+
+			if stored != tt.expectStored {
+				t.Errorf("expected WithError to report %v, got %v", tt.expectStored, stored)
+			}
+
+			if !tt.expectError {
+				if _, ok := entry["error"]; ok {
+					t.Errorf("expected no error field, got: %s", buf.String())
+				}
+
+				return
+			}
+
+			if entry["error"] != "file does not exist" || entry["error_type"] != "*errors.errorString" {
+				t.Errorf("expected error and error_type fields, got: %s", buf.String())
+			}
+		})
+	}
+}
+
+func TestRequestLogger_DurationMillis(t *testing.T) {
+	// GIVEN: a request logger with numeric millisecond durations
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	handler := vital.RequestLogger(logger, vital.WithDurationMillis())(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(2 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// WHEN: a request is served
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// THEN: duration_ms is a number of milliseconds
+	var entry map[string]any
+
+	err := json.Unmarshal(buf.Bytes(), &entry)
+	if err != nil {
+		t.Fatalf("failed to parse log output: %v", err)
+	}
+
+	durationMS, ok := entry["duration_ms"].(float64)
+	if !ok {
+		t.Fatalf("expected numeric duration_ms, got: %s", buf.String())
+	}
+
+	if durationMS < 2 || durationMS > 1000 {
+		t.Errorf("expected duration_ms of a few milliseconds, got %v", durationMS)
+	}
+
+	if _, ok := entry["duration"]; !ok {
+		t.Errorf("expected duration to be kept, got: %s", buf.String())
+	}
+}
+
+func TestRequestLogger_LogID(t *testing.T) {
+	// GIVEN: a request logger with log IDs enabled
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	handler := vital.RequestLogger(logger, vital.WithLogID())(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// WHEN: several requests are served
+	const requests = 3
+
+	for range requests {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	// THEN: each record carries a distinct log_id
+	seen := make(map[string]bool)
+
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var entry struct {
+			LogID string `json:"log_id"`
+		}
+
+		err := decoder.Decode(&entry)
+		if err != nil {
+			t.Fatalf("failed to parse log output: %v", err)
+		}
+
+		if len(entry.LogID) != 16 {
+			t.Errorf("expected 16-character log_id, got %q", entry.LogID)
+		}
+
+		if seen[entry.LogID] {
+			t.Errorf("expected distinct log_id, got duplicate %q", entry.LogID)
+		}
+
+		seen[entry.LogID] = true
+	}
+
+	if len(seen) != requests {
+		t.Errorf("expected %d records, got %d", requests, len(seen))
+	}
+}
+
+func TestRequestLogger_SkipPaths(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []vital.RequestLoggerOption
+		path      string
+		expectLog bool
+	}{
+		{
+			name:      "exact path is skipped",
+			opts:      []vital.RequestLoggerOption{vital.WithSkipPaths("/health/live")},
+			path:      "/health/live",
+			expectLog: false,
+		},
+		{
+			name:      "exact path does not match longer path",
+			opts:      []vital.RequestLoggerOption{vital.WithSkipPaths("/health/live")},
+			path:      "/health/live/extra",
+			expectLog: true,
+		},
+		{
+			name:      "prefix path is skipped",
+			opts:      []vital.RequestLoggerOption{vital.WithSkipPaths("/health/*")},
+			path:      "/health/ready",
+			expectLog: false,
+		},
+		{
+			name:      "other paths are logged",
+			opts:      []vital.RequestLoggerOption{vital.WithSkipPaths("/health/*")},
+			path:      "/api/users",
+			expectLog: true,
+		},
+		{
+			name: "sampler rejects request",
+			opts: []vital.RequestLoggerOption{vital.WithSampler(func(r *http.Request) bool {
+				return r.URL.Query().Get("log") == "1"
+			})},
+			path:      "/api/users",
+			expectLog: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a request logger with skip options
+			var buf bytes.Buffer
+
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+			handler := vital.RequestLogger(logger, tt.opts...)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			rec := httptest.NewRecorder()
+
+			// WHEN: a request to the path is served
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			// THEN: the request is served and logged only when not skipped
+			if rec.Code != http.StatusOK {
+				t.Errorf("expected status %d, got %d", http.StatusOK, rec.Code)
+			}
+
+			if logged := buf.Len() > 0; logged != tt.expectLog {
+				t.Errorf("expected logged=%v, got: %s", tt.expectLog, buf.String())
+			}
+		})
+	}
+}
+
+func TestRecovery_MasksSensitiveHeaders(t *testing.T) {
+	// GIVEN: a handler that panics with request headers
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	handler := vital.Recovery(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(r.Header)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer secret-token")
+
+	// WHEN: the handler is called
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// THEN: the panic log should mask the Authorization header
+	if strings.Contains(buf.String(), "secret-token") {
+		t.Errorf("expected Authorization to be masked, got: %s", buf.String())
+	}
+
+	if !strings.Contains(buf.String(), "***") {
+		t.Errorf("expected masked value in log, got: %s", buf.String())
+	}
+}
+
 func TestRecovery(t *testing.T) {
 	// GIVEN: a handler that panics
 	var buf bytes.Buffer
@@ -229,9 +680,11 @@ func TestRecovery(t *testing.T) {
 	// WHEN: the handler is called
 	recoveredHandler.ServeHTTP(rec, req)
 
-	// THEN: it should recover and return 500 with error logged
-	if rec.Code != http.StatusInternalServerError {
-		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	// THEN: it should recover and return a 500 problem with error logged
+	problem := vitaltest.AssertProblem(t, rec, http.StatusInternalServerError, "Internal Server Error")
+
+	if strings.Contains(problem.Detail, "something went wrong") {
+		t.Errorf("expected panic message not to leak into the problem, got %q", problem.Detail)
 	}
 
 	logOutput := buf.String()
@@ -244,6 +697,103 @@ func TestRecovery(t *testing.T) {
 	}
 }
 
+func TestRecovery_StreamingResponse(t *testing.T) {
+	// GIVEN: a streaming handler that panics after writing part of the response
+	var logBuf, serverLog bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&logBuf, nil))
+
+	handler := vital.Recovery(logger)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+
+		panic("stream broke")
+	}))
+
+	server := httptest.NewUnstartedServer(handler)
+	server.Config.ErrorLog = log.New(&serverLog, "", 0)
+	server.Start()
+
+	defer server.Close()
+
+	// WHEN: the client reads the response
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	body, readErr := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	server.Close()
+
+	// THEN: the response is truncated without a problem body or superfluous write
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	if readErr == nil {
+		t.Error("expected the aborted stream to produce a read error")
+	}
+
+	if string(body) != "partial" {
+		t.Errorf("expected body %q, got %q", "partial", string(body))
+	}
+
+	if strings.Contains(serverLog.String(), "superfluous") {
+		t.Errorf("expected no superfluous write warning, got: %s", serverLog.String())
+	}
+
+	if !strings.Contains(logBuf.String(), `"response_started":true`) {
+		t.Errorf("expected panic to be logged with response_started, got: %s", logBuf.String())
+	}
+}
+
+func TestRecovery_Hijack(t *testing.T) {
+	// GIVEN: a handler behind Recovery that hijacks the connection and writes a raw response
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+
+	handler := vital.Recovery(logger)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			http.Error(w, "not a hijacker", http.StatusInternalServerError)
+
+			return
+		}
+
+		conn, buf, err := hijacker.Hijack()
+		if err != nil {
+			return
+		}
+
+		defer func() { _ = conn.Close() }()
+
+		_, _ = buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked")
+		_ = buf.Flush()
+	}))
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// WHEN: the client sends a request
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	// THEN: the handler hijacked the connection and wrote the response itself
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	if string(body) != "hijacked" {
+		t.Errorf("expected body %q, got %q", "hijacked", string(body))
+	}
+}
+
 func TestRecovery_NormalExecution(t *testing.T) {
 	// GIVEN: a handler that executes normally without panic
 	var buf bytes.Buffer
@@ -278,6 +828,111 @@ func TestRecovery_NormalExecution(t *testing.T) {
 	}
 }
 
+func TestWhen(t *testing.T) {
+	tests := []struct {
+		name          string
+		cond          bool
+		expectedCalls []string
+	}{
+		{
+			name:          "middleware runs when the condition is true",
+			cond:          true,
+			expectedCalls: []string{"outer:", "verbose:", "inner:", "handler"},
+		},
+		{
+			name:          "middleware is skipped when the condition is false",
+			cond:          false,
+			expectedCalls: []string{"outer:", "inner:", "handler"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a chain with conditional middleware between two others
+			var calls []string
+
+			handler := vital.Chain(
+				recordingMiddleware("outer", &calls),
+				vital.When(tt.cond, recordingMiddleware("verbose", &calls)),
+				recordingMiddleware("inner", &calls),
+			).Then(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				calls = append(calls, "handler")
+
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			// WHEN: the request is processed
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			// THEN: the conditional middleware runs in place only when enabled
+			if !slices.Equal(calls, tt.expectedCalls) {
+				t.Errorf("expected calls %v, got %v", tt.expectedCalls, calls)
+			}
+		})
+	}
+}
+
+func TestDefaultStack(t *testing.T) {
+	t.Run("access logs contain trace_id", func(t *testing.T) {
+		// GIVEN: a context-aware logger and the default middleware stack
+		var buf bytes.Buffer
+
+		logger := slog.New(vital.NewContextHandler(
+			slog.NewJSONHandler(&buf, nil),
+			vital.WithBuiltinKeys(),
+		))
+
+		handler := vital.DefaultStack(logger).Then(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+		req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+		req.Header.Set("Traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+
+		rec := httptest.NewRecorder()
+
+		// WHEN: the handler processes the request
+		handler.ServeHTTP(rec, req)
+
+		// THEN: the access log should include the trace_id
+		logOutput := buf.String()
+		if !strings.Contains(logOutput, `"msg":"http request"`) {
+			t.Errorf("expected access log, got: %s", logOutput)
+		}
+
+		if !strings.Contains(logOutput, `"trace_id":"`+traceID+`"`) {
+			t.Errorf("expected log to contain trace_id %s, got: %s", traceID, logOutput)
+		}
+	})
+
+	t.Run("recovers from panics", func(t *testing.T) {
+		// GIVEN: the default middleware stack wrapping a panicking handler
+		var buf bytes.Buffer
+
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+		handler := vital.DefaultStack(logger).Then(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+
+		// WHEN: the handler processes the request
+		handler.ServeHTTP(rec, req)
+
+		// THEN: it should return 500 and log the panic
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+		}
+
+		if !strings.Contains(buf.String(), "panic recovered") {
+			t.Errorf("expected log to contain 'panic recovered', got: %s", buf.String())
+		}
+	})
+}
+
 func TestTraceContext(t *testing.T) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Verify trace context is in context
@@ -479,6 +1134,292 @@ func TestTraceContext(t *testing.T) {
 	})
 }
 
+func TestTraceContext_WithDefaultTraceFlags(t *testing.T) {
+	tests := []struct {
+		name          string
+		flags         string
+		traceparent   string
+		expectedFlags string
+	}{
+		{
+			name:          "new trace uses configured flags",
+			flags:         "00",
+			expectedFlags: "00",
+		},
+		{
+			name:          "continued trace keeps incoming flags",
+			flags:         "00",
+			traceparent:   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedFlags: "01",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: trace context middleware with default trace flags
+			var flags string
+
+			handler := vital.TraceContext(vital.WithDefaultTraceFlags(tt.flags))(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					flags = vital.GetTraceFlags(r.Context())
+
+					w.WriteHeader(http.StatusOK)
+				}),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.traceparent != "" {
+				req.Header.Set("Traceparent", tt.traceparent)
+			}
+
+			rec := httptest.NewRecorder()
+
+			// WHEN: the request is processed
+			handler.ServeHTTP(rec, req)
+
+			// THEN: the trace flags match in the context and the response header
+			if flags != tt.expectedFlags {
+				t.Errorf("expected flags %q, got %q", tt.expectedFlags, flags)
+			}
+
+			if !strings.HasSuffix(rec.Header().Get("Traceparent"), "-"+tt.expectedFlags) {
+				t.Errorf("expected traceparent ending in %q, got %q", tt.expectedFlags, rec.Header().Get("Traceparent"))
+			}
+		})
+	}
+}
+
+func TestWithDefaultTraceFlags_InvalidPanics(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags string
+	}{
+		{name: "not hex", flags: "zz"},
+		{name: "uppercase hex", flags: "0A"},
+		{name: "wrong length", flags: "1"},
+		{name: "empty", flags: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				// THEN: building the option panics with ErrInvalidTraceFlags
+				err, ok := recover().(error)
+				if !ok || !errors.Is(err, vital.ErrInvalidTraceFlags) {
+					t.Errorf("expected panic with ErrInvalidTraceFlags, got %v", err)
+				}
+			}()
+
+			// WHEN: building the option with invalid flags
+			vital.WithDefaultTraceFlags(tt.flags)
+		})
+	}
+}
+
+func TestResolveTraceConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []vital.TraceContextOption
+		expected vital.TraceConfig
+	}{
+		{
+			name: "defaults",
+			expected: vital.TraceConfig{
+				DefaultFlags:      "01",
+				SampleOriginated:  true,
+				TraceparentHeader: "Traceparent",
+				TracestateHeader:  "Tracestate",
+			},
+		},
+		{
+			name: "configured options",
+			opts: []vital.TraceContextOption{
+				vital.WithDefaultTraceFlags("00"),
+				vital.WithTraceHeaderNames("X-Traceparent", ""),
+				vital.WithRejectSameSpan(nil),
+			},
+			expected: vital.TraceConfig{
+				DefaultFlags:      "00",
+				SampleOriginated:  false,
+				TraceparentHeader: "X-Traceparent",
+				TracestateHeader:  "Tracestate",
+				RejectSameSpan:    true,
+			},
+		},
+		{
+			name: "sampled bit among other flags",
+			opts: []vital.TraceContextOption{vital.WithDefaultTraceFlags("03")},
+			expected: vital.TraceConfig{
+				DefaultFlags:      "03",
+				SampleOriginated:  true,
+				TraceparentHeader: "Traceparent",
+				TracestateHeader:  "Tracestate",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: trace context options
+
+			// WHEN: resolving the effective configuration
+			cfg := vital.ResolveTraceConfig(tt.opts...)
+
+			// THEN: the reported configuration matches the options
+			if cfg != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, cfg)
+			}
+		})
+	}
+}
+
+func TestTraceContext_WithTraceHeaderNames(t *testing.T) {
+	// GIVEN: trace context middleware reading and writing custom header names
+	var traceID string
+
+	handler := vital.TraceContext(vital.WithTraceHeaderNames("X-Legacy-Traceparent", "X-Legacy-Tracestate"))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceID = vital.GetTraceID(r.Context())
+
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Legacy-Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set("X-Legacy-Tracestate", "vendor=value")
+	req.Header.Set("Traceparent", "00-11111111111111111111111111111111-2222222222222222-01")
+
+	rec := httptest.NewRecorder()
+
+	// WHEN: the request is processed
+	handler.ServeHTTP(rec, req)
+
+	// THEN: the trace is continued from and propagated under the custom names only
+	if traceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected trace ID from custom header, got %q", traceID)
+	}
+
+	traceparent := rec.Header().Get("X-Legacy-Traceparent")
+	if !strings.HasPrefix(traceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-") {
+		t.Errorf("expected traceparent under custom name, got %q", traceparent)
+	}
+
+	if got := rec.Header().Get("X-Legacy-Tracestate"); got != "vendor=value" {
+		t.Errorf("expected tracestate under custom name, got %q", got)
+	}
+
+	if got := rec.Header().Get("Traceparent"); got != "" {
+		t.Errorf("expected no standard traceparent header, got %q", got)
+	}
+}
+
+func TestTraceContext_WithRejectSameSpan(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("child span never equals parent span", func(t *testing.T) {
+		// GIVEN: trace context middleware with same-span rejection enabled
+		parentSpanID := "00f067aa0ba902b7"
+		traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-" + parentSpanID + "-01"
+
+		wrappedHandler := vital.TraceContext(vital.WithRejectSameSpan(nil))(handler)
+
+		// WHEN: processing many requests continuing the same parent span
+		for range 1000 {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Traceparent", traceparent)
+
+			rec := httptest.NewRecorder()
+
+			wrappedHandler.ServeHTTP(rec, req)
+
+			// THEN: the generated child span-id should always differ from the parent
+			parts := strings.Split(rec.Header().Get("Traceparent"), "-")
+			if len(parts) != 4 {
+				t.Fatalf("expected valid traceparent, got %q", rec.Header().Get("Traceparent"))
+			}
+
+			if parts[2] == parentSpanID {
+				t.Fatalf("expected child span-id to differ from parent %s", parentSpanID)
+			}
+		}
+	})
+
+	t.Run("warns when incoming span matches a generated span", func(t *testing.T) {
+		// GIVEN: a logger capturing output
+		var buf bytes.Buffer
+
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+		wrappedHandler := vital.TraceContext(vital.WithRejectSameSpan(logger))(handler)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		wrappedHandler.ServeHTTP(rec, req)
+
+		// WHEN: a downstream echoes back our generated span-id as its parent
+		echoed := httptest.NewRequest(http.MethodGet, "/", nil)
+		echoed.Header.Set("Traceparent", rec.Header().Get("Traceparent"))
+		wrappedHandler.ServeHTTP(httptest.NewRecorder(), echoed)
+
+		// THEN: a warning should be logged
+		if !strings.Contains(buf.String(), "incoming span-id matches a recently generated span-id") {
+			t.Errorf("expected span reuse warning, got: %s", buf.String())
+		}
+	})
+
+	t.Run("does not warn for unrelated span", func(t *testing.T) {
+		// GIVEN: a logger capturing output
+		var buf bytes.Buffer
+
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+		wrappedHandler := vital.TraceContext(vital.WithRejectSameSpan(logger))(handler)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		// WHEN: processing a request with an unrelated parent span
+		wrappedHandler.ServeHTTP(httptest.NewRecorder(), req)
+
+		// THEN: no warning should be logged
+		if buf.Len() > 0 {
+			t.Errorf("expected no log output, got: %s", buf.String())
+		}
+	})
+}
+
+func BenchmarkTraceContext(b *testing.B) {
+	handler := vital.TraceContext()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = vital.GetTraceID(r.Context())
+		_ = vital.GetSpanID(r.Context())
+		_ = vital.GetTraceFlags(r.Context())
+	}))
+
+	b.Run("no traceparent", func(b *testing.B) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		b.ReportAllocs()
+
+		for b.Loop() {
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+		}
+	})
+
+	b.Run("with traceparent", func(b *testing.B) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		b.ReportAllocs()
+
+		for b.Loop() {
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+		}
+	})
+}
+
 func TestGetTraceID(t *testing.T) {
 	t.Run("returns trace ID from context", func(t *testing.T) {
 		// GIVEN: a context with a trace ID
@@ -1,7 +1,6 @@
 package vital
 
 import (
-	"context"
 	"net/http"
 	"time"
 
@@ -101,8 +100,20 @@ func OTel(opts ...OTelOption) Middleware {
 
 			spanContext := span.SpanContext()
 			if spanContext.IsValid() {
-				ctx = context.WithValue(ctx, TraceIDKey, spanContext.TraceID().String())
-				ctx = context.WithValue(ctx, SpanIDKey, spanContext.SpanID().String())
+				tc := &traceContext{
+					Version:    "00",
+					TraceID:    spanContext.TraceID().String(),
+					SpanID:     spanContext.SpanID().String(),
+					TraceFlags: spanContext.TraceFlags().String(),
+					TraceState: "",
+				}
+
+				ctx = withTraceContextValue(ctx, traceContextValue{
+					traceID:     tc.TraceID,
+					spanID:      tc.SpanID,
+					traceFlags:  tc.TraceFlags,
+					traceparent: tc.FormatTraceparent(),
+				})
 			}
 
 			r = r.WithContext(ctx)
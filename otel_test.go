@@ -1,7 +1,9 @@
 package vital
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -453,6 +455,66 @@ func TestOTel_IntegratesWithContextHandlerForLogCorrelation(t *testing.T) {
 	}
 }
 
+func TestOTel_SetsTraceFlagsInContext(t *testing.T) {
+	// GIVEN: OTel middleware with a sampling trace provider
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+
+	var capturedTraceFlags string
+
+	handler := OTel(WithTracerProvider(tp))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedTraceFlags = GetTraceFlags(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// WHEN: processing a request
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// THEN: the span's sampled flag is in the context
+	if capturedTraceFlags != "01" {
+		t.Errorf("expected trace flags %q, got %q", "01", capturedTraceFlags)
+	}
+}
+
+func TestOTel_LogsTraceparent(t *testing.T) {
+	// GIVEN: OTel middleware and a logger with the traceparent key registered
+	spanExporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spanExporter))
+
+	var buf bytes.Buffer
+
+	logger := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil), WithContextKeys(TraceparentKey)))
+
+	handler := OTel(WithTracerProvider(tp))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.InfoContext(r.Context(), "handling request")
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// WHEN: processing a request
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// THEN: the logged traceparent is built from the span
+	spans := spanExporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	spanContext := spans[0].SpanContext
+	expected := "00-" + spanContext.TraceID().String() + "-" + spanContext.SpanID().String() + "-01"
+
+	var entry struct {
+		Traceparent string `json:"traceparent"`
+	}
+
+	err := json.Unmarshal(buf.Bytes(), &entry)
+	if err != nil {
+		t.Fatalf("failed to parse log output: %v", err)
+	}
+
+	if entry.Traceparent != expected {
+		t.Errorf("expected traceparent %q, got %q", expected, entry.Traceparent)
+	}
+}
+
 func TestOTel_PropagatesTraceparentToResponse(t *testing.T) {
 	// GIVEN: OTel middleware with trace provider and propagator
 	spanExporter := tracetest.NewInMemoryExporter()
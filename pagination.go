@@ -0,0 +1,164 @@
+package vital
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultPageParam = "page"
+	defaultSizeParam = "size"
+	defaultPageSize  = 20
+	defaultMaxSize   = 100
+)
+
+// errNotInteger is returned for a query parameter that is not an integer.
+var errNotInteger = errors.New("must be an integer")
+
+// errPageTooLarge is returned for a page number whose offset does not fit in an int.
+var errPageTooLarge = errors.New("is too large")
+
+// paginationKey is the context key for the resolved page.
+type paginationKey struct{}
+
+// Page is the pagination resolved by the Pagination middleware. Page numbers start at 1.
+type Page struct {
+	// Number is the 1-based page number.
+	Number int
+	// Size is the number of items per page.
+	Size int
+}
+
+// Offset returns the number of items to skip.
+func (p Page) Offset() int {
+	return (p.Number - 1) * p.Size
+}
+
+// Limit returns the maximum number of items to return.
+func (p Page) Limit() int {
+	return p.Size
+}
+
+// PaginationOption configures the Pagination middleware.
+type PaginationOption func(*paginationConfig)
+
+// paginationConfig holds configuration for the Pagination middleware.
+type paginationConfig struct {
+	pageParam   string
+	sizeParam   string
+	defaultSize int
+	maxSize     int
+}
+
+// WithPageParam sets the query parameter holding the page number (default "page").
+func WithPageParam(name string) PaginationOption {
+	return func(c *paginationConfig) {
+		c.pageParam = name
+	}
+}
+
+// WithSizeParam sets the query parameter holding the page size (default "size").
+func WithSizeParam(name string) PaginationOption {
+	return func(c *paginationConfig) {
+		c.sizeParam = name
+	}
+}
+
+// WithDefaultPageSize sets the page size used when the size parameter is absent (default 20).
+func WithDefaultPageSize(size int) PaginationOption {
+	return func(c *paginationConfig) {
+		c.defaultSize = size
+	}
+}
+
+// WithMaxPageSize sets the largest page size; larger values are clamped to it (default 100).
+func WithMaxPageSize(size int) PaginationOption {
+	return func(c *paginationConfig) {
+		c.maxSize = size
+	}
+}
+
+// Pagination returns a middleware that parses the page and size query parameters and stores
+// the resolved Page in the request context, retrievable with GetPagination. Missing values use
+// page 1 and the default size; page numbers below 1 are raised to 1 and sizes are clamped to
+// [1, max]. Non-numeric values and page numbers whose offset would overflow are rejected with
+// a 400 Bad Request ProblemDetail.
+func Pagination(opts ...PaginationOption) Middleware {
+	cfg := &paginationConfig{
+		pageParam:   defaultPageParam,
+		sizeParam:   defaultSizeParam,
+		defaultSize: defaultPageSize,
+		maxSize:     defaultMaxSize,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			page, err := resolvePage(r, cfg)
+			if err != nil {
+				RespondProblem(w, BadRequest(err.Error()))
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), paginationKey{}, page)))
+		})
+	}
+}
+
+// resolvePage parses and clamps the pagination query parameters.
+func resolvePage(r *http.Request, cfg *paginationConfig) (Page, error) {
+	query := r.URL.Query()
+
+	number, err := queryInt(query.Get(cfg.pageParam), cfg.pageParam, 1)
+	if err != nil {
+		return Page{}, err
+	}
+
+	size, err := queryInt(query.Get(cfg.sizeParam), cfg.sizeParam, cfg.defaultSize)
+	if err != nil {
+		return Page{}, err
+	}
+
+	page := Page{
+		Number: max(number, 1),
+		Size:   min(max(size, 1), max(cfg.maxSize, 1)),
+	}
+
+	if page.Number-1 > math.MaxInt/page.Size {
+		return Page{}, fmt.Errorf("query parameter %q %w", cfg.pageParam, errPageTooLarge)
+	}
+
+	return page, nil
+}
+
+// queryInt parses an integer query parameter, returning fallback when it is empty.
+func queryInt(value, name string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("query parameter %q %w", name, errNotInteger)
+	}
+
+	return parsed, nil
+}
+
+// GetPagination retrieves the page resolved by the Pagination middleware from the context.
+// It returns page 1 with the default size of 20 when the middleware did not run.
+func GetPagination(ctx context.Context) Page {
+	if page, ok := ctx.Value(paginationKey{}).(Page); ok {
+		return page
+	}
+
+	return Page{Number: 1, Size: defaultPageSize}
+}
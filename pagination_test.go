@@ -0,0 +1,103 @@
+package vital_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/monkescience/vital"
+)
+
+func TestPagination(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		opts           []vital.PaginationOption
+		expectedStatus int
+		expectedOffset int
+		expectedLimit  int
+	}{
+		{
+			name:           "defaults",
+			expectedStatus: http.StatusOK,
+			expectedOffset: 0,
+			expectedLimit:  20,
+		},
+		{
+			name:           "explicit page and size",
+			query:          "?page=3&size=10",
+			expectedStatus: http.StatusOK,
+			expectedOffset: 20,
+			expectedLimit:  10,
+		},
+		{
+			name:           "size clamped to max",
+			query:          "?page=2&size=500",
+			opts:           []vital.PaginationOption{vital.WithMaxPageSize(50)},
+			expectedStatus: http.StatusOK,
+			expectedOffset: 50,
+			expectedLimit:  50,
+		},
+		{
+			name:           "page and size raised to minimum",
+			query:          "?page=-1&size=0",
+			expectedStatus: http.StatusOK,
+			expectedOffset: 0,
+			expectedLimit:  1,
+		},
+		{
+			name:           "custom parameter names",
+			query:          "?p=2&per_page=5",
+			opts:           []vital.PaginationOption{vital.WithPageParam("p"), vital.WithSizeParam("per_page")},
+			expectedStatus: http.StatusOK,
+			expectedOffset: 5,
+			expectedLimit:  5,
+		},
+		{
+			name:           "non-numeric page is rejected",
+			query:          "?page=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "page with an overflowing offset is rejected",
+			query:          "?page=9223372036854775807&size=50",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a pagination middleware with the given options
+			var page vital.Page
+
+			handler := vital.Pagination(tt.opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				page = vital.GetPagination(r.Context())
+
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			rec := httptest.NewRecorder()
+
+			// WHEN: listing with the given query
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items"+tt.query, nil))
+
+			// THEN: the resolved offset and limit are in the context
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+
+			if tt.expectedStatus != http.StatusOK {
+				if rec.Header().Get("Content-Type") != "application/problem+json" {
+					t.Errorf("expected problem response, got %q", rec.Header().Get("Content-Type"))
+				}
+
+				return
+			}
+
+			if page.Offset() != tt.expectedOffset || page.Limit() != tt.expectedLimit {
+				t.Errorf("expected offset %d and limit %d, got %d and %d",
+					tt.expectedOffset, tt.expectedLimit, page.Offset(), page.Limit())
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package vital
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RecordedRequest is a request kept by RequestRecorder.
+type RecordedRequest struct {
+	Time     time.Time         `json:"time"`
+	Method   string            `json:"method"`
+	Path     string            `json:"path"`
+	Status   int               `json:"status"`
+	Duration string            `json:"duration"`
+	Headers  map[string]string `json:"headers,omitempty"`
+}
+
+// RecorderOption configures the RequestRecorder middleware.
+type RecorderOption func(*recorderConfig)
+
+// recorderConfig holds configuration for the RequestRecorder middleware.
+type recorderConfig struct {
+	sampler func(*http.Request) bool
+	headers []string
+}
+
+// WithRecorderSampler records only requests for which sampler returns true (default: all requests).
+func WithRecorderSampler(sampler func(*http.Request) bool) RecorderOption {
+	return func(c *recorderConfig) {
+		c.sampler = sampler
+	}
+}
+
+// WithRecorderHeaders records the values of the given request headers.
+// Values of sensitive headers are masked as "***".
+func WithRecorderHeaders(headers ...string) RecorderOption {
+	return func(c *recorderConfig) {
+		c.headers = append(c.headers, headers...)
+	}
+}
+
+// RequestRecorder returns a middleware that keeps the last capacity sampled requests (method,
+// path, status, duration, and selected headers) in a bounded in-memory ring buffer, and a
+// handler that serves the buffer as JSON, newest first. Mount the handler on an admin route
+// to inspect recent traffic without enabling full request logging. A capacity below 1 is
+// treated as 1.
+func RequestRecorder(capacity int, opts ...RecorderOption) (Middleware, http.Handler) {
+	cfg := &recorderConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	buffer := newRequestRing(max(capacity, 1))
+
+	middleware := func(next http.Handler) http.Handler {
+		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cfg.sampler != nil && !cfg.sampler(r) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			start := time.Now()
+
+			wrapped, writer := wrapResponseWriter(w)
+
+			next.ServeHTTP(writer, r)
+
+			buffer.add(RecordedRequest{
+				Time:     start,
+				Method:   r.Method,
+				Path:     r.URL.Path,
+				Status:   wrapped.statusCode,
+				Duration: time.Since(start).String(),
+				Headers:  recordedHeaders(r.Header, cfg.headers),
+			})
+		})
+	}
+
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		disableResponseCacheHeaders(writer)
+		respondJSON(writer, http.StatusOK, buffer.snapshot())
+	})
+
+	return middleware, handler
+}
+
+// recordedHeaders returns the values of the selected headers present on the request.
+func recordedHeaders(header http.Header, names []string) map[string]string {
+	if len(names) == 0 {
+		return nil
+	}
+
+	values := make(map[string]string, len(names))
+
+	for _, name := range names {
+		headerValues := header.Values(name)
+		if len(headerValues) == 0 {
+			continue
+		}
+
+		values[http.CanonicalHeaderKey(name)] = redactHeaderValue(name, headerValues)
+	}
+
+	return values
+}
+
+// requestRing is a fixed-size ring buffer of recorded requests.
+type requestRing struct {
+	mu      sync.Mutex
+	entries []RecordedRequest
+	next    int
+	full    bool
+}
+
+// newRequestRing creates a requestRing holding at most capacity entries.
+func newRequestRing(capacity int) *requestRing {
+	return &requestRing{
+		entries: make([]RecordedRequest, capacity),
+	}
+}
+
+// add records an entry, overwriting the oldest one when the buffer is full.
+func (b *requestRing) add(entry RecordedRequest) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries[b.next] = entry
+	b.next = (b.next + 1) % len(b.entries)
+
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// snapshot returns a copy of the recorded entries, newest first.
+func (b *requestRing) snapshot() []RecordedRequest {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	count := b.next
+	if b.full {
+		count = len(b.entries)
+	}
+
+	snapshot := make([]RecordedRequest, 0, count)
+
+	for i := 1; i <= count; i++ {
+		snapshot = append(snapshot, b.entries[(b.next-i+len(b.entries))%len(b.entries)])
+	}
+
+	return snapshot
+}
@@ -0,0 +1,70 @@
+package vital_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/monkescience/vital"
+)
+
+func TestRequestRecorder(t *testing.T) {
+	// GIVEN: a recorder keeping the last two sampled requests
+	recorder, recordHandler := vital.RequestRecorder(2,
+		vital.WithRecorderSampler(func(r *http.Request) bool { return !strings.HasPrefix(r.URL.Path, "/health") }),
+		vital.WithRecorderHeaders("User-Agent", "Authorization"),
+	)
+
+	handler := recorder(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// WHEN: several requests are served
+	for _, path := range []string{"/first", "/health/live", "/second", "/missing"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("User-Agent", "test-agent")
+		req.Header.Set("Authorization", "Bearer secret")
+
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	rec := httptest.NewRecorder()
+	recordHandler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/requests", nil))
+
+	// THEN: the buffer holds the two most recent sampled requests, newest first
+	var entries []vital.RecordedRequest
+
+	err := json.Unmarshal(rec.Body.Bytes(), &entries)
+	if err != nil {
+		t.Fatalf("failed to parse recorder output: %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %s", len(entries), rec.Body.String())
+	}
+
+	if entries[0].Path != "/missing" || entries[0].Status != http.StatusNotFound {
+		t.Errorf("expected newest entry /missing with 404, got %+v", entries[0])
+	}
+
+	if entries[1].Path != "/second" || entries[1].Status != http.StatusOK {
+		t.Errorf("expected second entry /second with 200, got %+v", entries[1])
+	}
+
+	if entries[0].Method != http.MethodGet || entries[0].Duration == "" {
+		t.Errorf("expected method and duration to be recorded, got %+v", entries[0])
+	}
+
+	// THEN: selected headers are recorded with sensitive values masked
+	if entries[0].Headers["User-Agent"] != "test-agent" || entries[0].Headers["Authorization"] != "***" {
+		t.Errorf("expected recorded headers with masked Authorization, got %v", entries[0].Headers)
+	}
+}
@@ -1,18 +1,75 @@
 package vital
 
 import (
-	"encoding/json"
+	"context"
+	"encoding/hex"
 	"fmt"
+	"log/slog"
 	"maps"
 	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
 )
 
+// aboutBlankType is the problem type implied by RFC 9457 when Type is absent.
+const aboutBlankType = "about:blank"
+
+// pointerErrorsExtension is the extension member holding the errors added by WithPointerError.
+const pointerErrorsExtension = "errors"
+
+//nolint:gochecknoglobals // Package-level setting shared by all problem responses
+var emitAboutBlankType atomic.Bool
+
+//nolint:gochecknoglobals // Package-level setting shared by all context-aware problem responses
+var generateProblemInstance atomic.Bool
+
+//nolint:gochecknoglobals // Package-level registration shared by all context-aware problem responses
+var (
+	problemContextKeysMu sync.RWMutex
+	problemContextKeys   []ContextKey
+)
+
+//nolint:gochecknoglobals // Package-level setting shared by all context-aware problem responses
+var (
+	titleResolverMu sync.RWMutex
+	titleResolver   TitleResolver
+)
+
+// TitleResolver returns the title for a status code in a language, or "" to keep the English title.
+type TitleResolver func(status int, lang string) string
+
+// SetTitleResolver sets the resolver RespondProblemCtx uses to localize problem titles into the
+// language negotiated by the Language middleware. Only standard titles, as set by the status-based
+// constructors, are translated; custom titles are kept. A nil resolver keeps English titles (default).
+func SetTitleResolver(resolver TitleResolver) {
+	titleResolverMu.Lock()
+	defer titleResolverMu.Unlock()
+
+	titleResolver = resolver
+}
+
+// SetEmitAboutBlankType controls whether problems without a Type render "type":"about:blank"
+// explicitly instead of omitting the member. RFC 9457 treats both as equivalent, but emitting
+// it spares clients from special-casing absence. Disabled by default.
+func SetEmitAboutBlankType(enabled bool) {
+	emitAboutBlankType.Store(enabled)
+}
+
+// SetGenerateProblemInstance controls whether RespondProblemCtx fills an empty Instance with a
+// generated "urn:uuid:" URN and logs it with the request context, so an error a user reports
+// can be found in the server logs. Instances set explicitly are kept. Disabled by default.
+func SetGenerateProblemInstance(enabled bool) {
+	generateProblemInstance.Store(enabled)
+}
+
 // ProblemDetail represents an RFC 9457 problem details response.
 // See https://datatracker.ietf.org/doc/html/rfc9457 for specification.
 type ProblemDetail struct {
 	// Type is a URI reference that identifies the problem type.
 	// When dereferenced, it should provide human-readable documentation.
-	// Defaults to "about:blank" when not specified.
+	// Defaults to "about:blank" when not specified; the member is omitted from the JSON body
+	// unless SetEmitAboutBlankType is enabled.
 	Type string `json:"type,omitempty"`
 
 	// Title is a short, human-readable summary of the problem type.
@@ -31,11 +88,28 @@ type ProblemDetail struct {
 	// Extensions holds any additional members for extensibility.
 	// Use this for problem-type-specific information.
 	Extensions map[string]any `json:"-"`
+
+	// OmitStatus omits the status member from the JSON body.
+	// The HTTP status code is still set by RespondProblem.
+	OmitStatus bool `json:"-"`
+
+	// Headers holds response headers written by RespondProblem, such as WWW-Authenticate,
+	// Allow, or Retry-After. They are not part of the JSON body.
+	Headers http.Header `json:"-"`
+}
+
+// PointerError describes an error in a request member identified by a JSON Pointer (RFC 6901),
+// as listed in the "errors" extension by WithPointerError.
+type PointerError struct {
+	// Pointer is the JSON Pointer to the offending member, such as "/email" or "/items/0/sku".
+	Pointer string `json:"pointer"`
+	// Detail explains what is wrong with the member.
+	Detail string `json:"detail"`
 }
 
 // NewProblemDetail creates a new ProblemDetail with the specified status and title.
 func NewProblemDetail(status int, title string) *ProblemDetail {
-	//nolint:exhaustruct // Optional fields Type, Detail, Instance are intentionally omitted
+	//nolint:exhaustruct // Optional fields Type, Detail, Instance, Headers are intentionally omitted
 	return &ProblemDetail{
 		Status:     status,
 		Title:      title,
@@ -45,15 +119,31 @@ func NewProblemDetail(status int, title string) *ProblemDetail {
 
 // MarshalJSON implements custom JSON marshaling to include extensions.
 func (p ProblemDetail) MarshalJSON() ([]byte, error) {
+	data, err := currentJSONEncoder().Marshal(p.jsonFields())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal problem detail: %w", err)
+	}
+
+	return data, nil
+}
+
+// jsonFields returns the members of the JSON body, including extensions with sensitive headers masked.
+func (p ProblemDetail) jsonFields() map[string]any {
 	// Create a map with the standard fields
 	fields := make(map[string]any)
 
-	if p.Type != "" {
+	switch {
+	case p.Type != "":
 		fields["type"] = p.Type
+	case emitAboutBlankType.Load():
+		fields["type"] = aboutBlankType
 	}
 
 	fields["title"] = p.Title
-	fields["status"] = p.Status
+
+	if !p.OmitStatus {
+		fields["status"] = p.Status
+	}
 
 	if p.Detail != "" {
 		fields["detail"] = p.Detail
@@ -63,15 +153,22 @@ func (p ProblemDetail) MarshalJSON() ([]byte, error) {
 		fields["instance"] = p.Instance
 	}
 
-	// Add any extensions
-	maps.Copy(fields, p.Extensions)
+	// Add any extensions, masking sensitive header values
+	for key, value := range p.Extensions {
+		if isSensitiveHeader(key) {
+			fields[key] = redactedValue
 
-	data, err := json.Marshal(fields)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal problem detail: %w", err)
+			continue
+		}
+
+		if header, ok := value.(http.Header); ok {
+			value = redactHeaders(header)
+		}
+
+		fields[key] = value
 	}
 
-	return data, nil
+	return fields
 }
 
 // WithType sets the type URI and returns the ProblemDetail for chaining.
@@ -106,12 +203,146 @@ func (p *ProblemDetail) WithExtension(key string, value any) *ProblemDetail {
 	return p
 }
 
+// WithPointerError appends a PointerError to the "errors" extension and returns the
+// ProblemDetail for chaining, producing {"errors":[{"pointer":"/email","detail":"is required"}]}.
+// An "errors" extension of a different shape is replaced.
+func (p *ProblemDetail) WithPointerError(pointer, detail string) *ProblemDetail {
+	pointerErrors, _ := p.Extensions[pointerErrorsExtension].([]PointerError)
+
+	return p.WithExtension(pointerErrorsExtension, append(pointerErrors, PointerError{Pointer: pointer, Detail: detail}))
+}
+
+// WithHeader adds a response header written by RespondProblem and returns the ProblemDetail
+// for chaining. Calling it again with the same key adds another value.
+func (p *ProblemDetail) WithHeader(key, value string) *ProblemDetail {
+	if p.Headers == nil {
+		p.Headers = make(http.Header)
+	}
+
+	p.Headers.Add(key, value)
+
+	return p
+}
+
+// WithoutStatus omits the status member from the JSON body and returns the ProblemDetail for chaining.
+func (p *ProblemDetail) WithoutStatus() *ProblemDetail {
+	p.OmitStatus = true
+
+	return p
+}
+
 // RespondProblem writes a ProblemDetail as an HTTP response.
-// It sets the appropriate content type and status code.
+// It sets the problem's headers, the appropriate content type, and the status code.
+// Problem headers replace response headers already set under the same name.
 func RespondProblem(w http.ResponseWriter, problem *ProblemDetail) {
+	setProblemHeaders(w, problem)
+
 	w.Header().Set("Content-Type", "application/problem+json")
 	w.WriteHeader(problem.Status)
-	_ = json.NewEncoder(w).Encode(problem) //nolint:errchkjson
+	_ = currentJSONEncoder().NewEncoder(w).Encode(problem.jsonFields())
+}
+
+// setProblemHeaders copies the problem's headers to the response, replacing response headers
+// already set under the same name. The values are cloned, so later changes to the response
+// headers do not alter the problem, which may be shared between responses.
+func setProblemHeaders(w http.ResponseWriter, problem *ProblemDetail) {
+	for key, values := range problem.Headers {
+		w.Header()[http.CanonicalHeaderKey(key)] = slices.Clone(values)
+	}
+}
+
+// ProblemContextKeys replaces the set of context keys whose values RespondProblemCtx adds to
+// every problem as extensions, e.g. TraceIDKey and TenantIDKey. Extensions named like a key
+// are never overwritten, and keys without a value in the context are skipped.
+func ProblemContextKeys(keys ...ContextKey) {
+	registered := append([]ContextKey(nil), keys...)
+
+	problemContextKeysMu.Lock()
+	defer problemContextKeysMu.Unlock()
+
+	problemContextKeys = registered
+}
+
+// RespondProblemCtx writes a ProblemDetail like RespondProblem, adding the values of the keys
+// registered with ProblemContextKeys from ctx as extensions and localizing the title with the
+// resolver set by SetTitleResolver. With SetGenerateProblemInstance enabled, an empty Instance
+// is filled with a generated URN that is also logged. Behind the NegotiateFormat middleware,
+// problems are written as application/problem+xml when XML was negotiated. The problem itself
+// is not modified.
+func RespondProblemCtx(ctx context.Context, w http.ResponseWriter, problem *ProblemDetail) {
+	problemContextKeysMu.RLock()
+	keys := problemContextKeys
+	problemContextKeysMu.RUnlock()
+
+	enriched := *problem
+	enriched.Extensions = maps.Clone(problem.Extensions)
+	enriched.Title = localizedTitle(ctx, problem)
+
+	for _, key := range keys {
+		if _, exists := enriched.Extensions[key.Name]; exists {
+			continue
+		}
+
+		value := contextValue(ctx, key)
+		if value == nil {
+			continue
+		}
+
+		if enriched.Extensions == nil {
+			enriched.Extensions = make(map[string]any, len(keys))
+		}
+
+		enriched.Extensions[key.Name] = value
+	}
+
+	if enriched.Instance == "" && generateProblemInstance.Load() {
+		enriched.Instance = generateInstanceURN()
+
+		slog.InfoContext(ctx, "problem instance generated",
+			slog.String("instance", enriched.Instance),
+			slog.Int("status", enriched.Status),
+			slog.String("title", enriched.Title),
+		)
+	}
+
+	if GetFormat(ctx) == FormatXML {
+		respondProblemXML(w, &enriched)
+
+		return
+	}
+
+	RespondProblem(w, &enriched)
+}
+
+// generateInstanceURN returns a random version 4 UUID URN built from a generated trace ID.
+func generateInstanceURN() string {
+	// generateTraceID always returns 32 hex characters, so decoding cannot fail
+	id, _ := hex.DecodeString(generateTraceID())
+
+	id[6] = id[6]&0x0f | 0x40 //nolint:mnd // UUID version 4
+	id[8] = id[8]&0x3f | 0x80 //nolint:mnd // RFC 9562 variant
+
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:16])
+}
+
+// localizedTitle returns the problem title translated into the negotiated language, or the
+// title as is when it is not the standard title for the status or no translation exists.
+func localizedTitle(ctx context.Context, problem *ProblemDetail) string {
+	titleResolverMu.RLock()
+	resolver := titleResolver
+	titleResolverMu.RUnlock()
+
+	lang := GetLanguage(ctx)
+	if resolver == nil || lang == "" || problem.Title != http.StatusText(problem.Status) {
+		return problem.Title
+	}
+
+	title := resolver(problem.Status, lang)
+	if title == "" {
+		return problem.Title
+	}
+
+	return title
 }
 
 // Common problem detail constructors for standard HTTP errors
@@ -163,3 +394,51 @@ func ServiceUnavailable(detail string) *ProblemDetail {
 	return NewProblemDetail(http.StatusServiceUnavailable, "Service Unavailable").
 		WithDetail(detail)
 }
+
+// BatchItemResult is the outcome of a single item in a batch request.
+type BatchItemResult struct {
+	// Index is the position of the item in the request.
+	Index int `json:"index"`
+	// Status is the HTTP status code for this item.
+	Status int `json:"status"`
+	// Title is a short summary of the item's outcome.
+	Title string `json:"title,omitempty"`
+	// Detail explains the item's outcome.
+	Detail string `json:"detail,omitempty"`
+}
+
+// BatchSuccess returns the result for an item processed successfully with the given status.
+func BatchSuccess(index, status int) BatchItemResult {
+	return BatchItemResult{
+		Index:  index,
+		Status: status,
+		Title:  http.StatusText(status),
+	}
+}
+
+// BatchFailure returns the result for an item that failed with the given problem.
+func BatchFailure(index int, problem *ProblemDetail) BatchItemResult {
+	return BatchItemResult{
+		Index:  index,
+		Status: problem.Status,
+		Title:  problem.Title,
+		Detail: problem.Detail,
+	}
+}
+
+// MultiProblem creates a problem detail summarizing a batch request, such as a 207 Multi-Status
+// or 422 Unprocessable Entity response. The per-item results are listed under the "items"
+// extension and the detail counts the failed items (status 400 and above).
+func MultiProblem(status int, results []BatchItemResult) *ProblemDetail {
+	failed := 0
+
+	for _, result := range results {
+		if result.Status >= http.StatusBadRequest {
+			failed++
+		}
+	}
+
+	return NewProblemDetail(status, http.StatusText(status)).
+		WithDetail(fmt.Sprintf("%d of %d items failed", failed, len(results))).
+		WithExtension("items", results)
+}
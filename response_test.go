@@ -1,10 +1,15 @@
 package vital_test
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/monkescience/vital"
@@ -87,6 +92,37 @@ func TestProblemDetail_MarshalJSON(t *testing.T) {
 				"error_count":    float64(2),
 			},
 		},
+		{
+			name: "problem detail with sensitive header extensions",
+			problem: &vital.ProblemDetail{
+				Status: http.StatusBadRequest,
+				Title:  "Bad Request",
+				Extensions: map[string]any{
+					"authorization": "Bearer secret-token",
+					"headers": http.Header{
+						"Authorization": {"Bearer secret-token"},
+						"Accept":        {"application/json"},
+					},
+				},
+			},
+			expected: map[string]any{
+				"status":        float64(400),
+				"title":         "Bad Request",
+				"authorization": "***",
+				"headers": map[string]any{
+					"Authorization": []any{"***"},
+					"Accept":        []any{"application/json"},
+				},
+			},
+		},
+		{
+			name:    "problem detail without status",
+			problem: vital.BadRequest("invalid input").WithoutStatus(),
+			expected: map[string]any{
+				"title":  "Bad Request",
+				"detail": "invalid input",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -224,6 +260,421 @@ func TestRespondProblem(t *testing.T) {
 	}
 }
 
+func TestRespondProblem_WithoutStatus(t *testing.T) {
+	// GIVEN: a problem detail with the status member omitted
+	problem := vital.Conflict("email already exists").WithoutStatus()
+
+	recorder := httptest.NewRecorder()
+
+	// WHEN: responding with the problem detail
+	vital.RespondProblem(recorder, problem)
+
+	// THEN: the HTTP status should be set but the body should lack status
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("expected status code %d, got %d", http.StatusConflict, recorder.Code)
+	}
+
+	var result map[string]any
+
+	err := json.Unmarshal(recorder.Body.Bytes(), &result)
+	if err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	if _, exists := result["status"]; exists {
+		t.Errorf("expected body without status, got %v", result)
+	}
+}
+
+func TestSetEmitAboutBlankType(t *testing.T) {
+	tests := []struct {
+		name         string
+		enabled      bool
+		problem      *vital.ProblemDetail
+		expectedType any
+	}{
+		{
+			name:         "omitted by default",
+			enabled:      false,
+			problem:      vital.NotFound("user not found"),
+			expectedType: nil,
+		},
+		{
+			name:         "about:blank when enabled",
+			enabled:      true,
+			problem:      vital.NotFound("user not found"),
+			expectedType: "about:blank",
+		},
+		{
+			name:         "explicit type wins when enabled",
+			enabled:      true,
+			problem:      vital.NotFound("user not found").WithType("https://example.com/not-found"),
+			expectedType: "https://example.com/not-found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: the about:blank setting
+			vital.SetEmitAboutBlankType(tt.enabled)
+			t.Cleanup(func() { vital.SetEmitAboutBlankType(false) })
+
+			recorder := httptest.NewRecorder()
+
+			// WHEN: responding with the problem detail
+			vital.RespondProblem(recorder, tt.problem)
+
+			// THEN: the type member matches the setting
+			var result map[string]any
+
+			err := json.Unmarshal(recorder.Body.Bytes(), &result)
+			if err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+
+			if result["type"] != tt.expectedType {
+				t.Errorf("expected type %v, got %v", tt.expectedType, result["type"])
+			}
+		})
+	}
+}
+
+func TestProblemDetail_WithPointerError(t *testing.T) {
+	// GIVEN: a validation problem with pointer-based field errors
+	problem := vital.UnprocessableEntity("validation failed").
+		WithPointerError("/email", "is required").
+		WithPointerError("/items/0/quantity", "must be positive")
+
+	rec := httptest.NewRecorder()
+
+	// WHEN: responding with the problem
+	vital.RespondProblem(rec, problem)
+
+	// THEN: the errors array serializes alongside the standard members
+	var body struct {
+		Title  string `json:"title"`
+		Status int    `json:"status"`
+		Detail string `json:"detail"`
+		Errors []struct {
+			Pointer string `json:"pointer"`
+			Detail  string `json:"detail"`
+		} `json:"errors"`
+	}
+
+	err := json.Unmarshal(rec.Body.Bytes(), &body)
+	if err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	if body.Title != "Unprocessable Entity" || body.Status != http.StatusUnprocessableEntity ||
+		body.Detail != "validation failed" {
+		t.Errorf("expected standard members, got: %s", rec.Body.String())
+	}
+
+	if len(body.Errors) != 2 {
+		t.Fatalf("expected two errors, got: %s", rec.Body.String())
+	}
+
+	if body.Errors[0].Pointer != "/email" || body.Errors[0].Detail != "is required" {
+		t.Errorf("unexpected first error: %+v", body.Errors[0])
+	}
+
+	if body.Errors[1].Pointer != "/items/0/quantity" || body.Errors[1].Detail != "must be positive" {
+		t.Errorf("unexpected second error: %+v", body.Errors[1])
+	}
+}
+
+func TestRespondProblem_Headers(t *testing.T) {
+	tests := []struct {
+		name            string
+		problem         *vital.ProblemDetail
+		expectedHeaders http.Header
+	}{
+		{
+			name: "custom headers are emitted",
+			problem: vital.NewProblemDetail(http.StatusMethodNotAllowed, "Method Not Allowed").
+				WithHeader("Allow", "GET").
+				WithHeader("allow", "HEAD").
+				WithHeader("X-RateLimit-Remaining", "0"),
+			expectedHeaders: http.Header{
+				"Allow":                 {"GET", "HEAD"},
+				"X-Ratelimit-Remaining": {"0"},
+				"Content-Type":          {"application/problem+json"},
+			},
+		},
+		{
+			name:    "problems without headers are unchanged",
+			problem: vital.NotFound("user not found"),
+			expectedHeaders: http.Header{
+				"Content-Type": {"application/problem+json"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a problem with or without custom headers
+			rec := httptest.NewRecorder()
+
+			// WHEN: responding with the problem
+			vital.RespondProblem(rec, tt.problem)
+
+			// THEN: exactly the expected headers are written and the body has no header members
+			if !reflect.DeepEqual(rec.Header(), tt.expectedHeaders) {
+				t.Errorf("expected headers %v, got %v", tt.expectedHeaders, rec.Header())
+			}
+
+			if rec.Code != tt.problem.Status {
+				t.Errorf("expected status %d, got %d", tt.problem.Status, rec.Code)
+			}
+
+			if strings.Contains(rec.Body.String(), "RateLimit") {
+				t.Errorf("expected headers to stay out of the body, got: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRespondProblem_HeadersNotShared(t *testing.T) {
+	// GIVEN: a problem with headers shared between responses
+	problem := vital.NewProblemDetail(http.StatusTooManyRequests, "Too Many Requests").
+		WithHeader("X-RateLimit-Remaining", "0")
+
+	first := httptest.NewRecorder()
+	vital.RespondProblem(first, problem)
+
+	// WHEN: a later handler changes the first response's header in place
+	first.Header()["X-Ratelimit-Remaining"][0] = "changed"
+
+	second := httptest.NewRecorder()
+	vital.RespondProblem(second, problem)
+
+	// THEN: the problem and the second response keep the original value
+	if got := problem.Headers.Get("X-RateLimit-Remaining"); got != "0" {
+		t.Errorf("expected the problem header to stay %q, got %q", "0", got)
+	}
+
+	if got := second.Header().Get("X-RateLimit-Remaining"); got != "0" {
+		t.Errorf("expected the second response header to be %q, got %q", "0", got)
+	}
+}
+
+func TestRespondProblemCtx(t *testing.T) {
+	// GIVEN: trace and tenant keys registered for problems and a traced request
+	requestIDKey := vital.ContextKey{Name: "request_id"}
+
+	vital.ProblemContextKeys(vital.TraceIDKey, vital.TenantIDKey, requestIDKey)
+	t.Cleanup(func() { vital.ProblemContextKeys() })
+
+	var body map[string]any
+
+	handler := vital.TraceContext()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), vital.TenantIDKey, "acme")
+
+		problem := vital.NotFound("user not found").WithExtension("tenant_id", "explicit")
+
+		// WHEN: responding with a context-aware problem
+		vital.RespondProblemCtx(ctx, w, problem)
+
+		if _, mutated := problem.Extensions["trace_id"]; mutated {
+			t.Error("expected the original problem to be unmodified")
+		}
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	err := json.Unmarshal(rec.Body.Bytes(), &body)
+	if err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	// THEN: registered keys flow into the body without overriding explicit extensions
+	if body["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected trace_id from context, got %v", body["trace_id"])
+	}
+
+	if body["tenant_id"] != "explicit" {
+		t.Errorf("expected explicit tenant_id to win, got %v", body["tenant_id"])
+	}
+
+	if _, exists := body["request_id"]; exists {
+		t.Errorf("expected keys without a context value to be skipped, got %v", body["request_id"])
+	}
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestRespondProblemCtx_GeneratedInstance(t *testing.T) {
+	var buf bytes.Buffer
+
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	vital.SetGenerateProblemInstance(true)
+	t.Cleanup(func() { vital.SetGenerateProblemInstance(false) })
+
+	respond := func(t *testing.T, problem *vital.ProblemDetail) vital.ProblemDetail {
+		t.Helper()
+
+		rec := httptest.NewRecorder()
+		vital.RespondProblemCtx(context.Background(), rec, problem)
+
+		var decoded vital.ProblemDetail
+
+		err := json.Unmarshal(rec.Body.Bytes(), &decoded)
+		if err != nil {
+			t.Fatalf("failed to decode problem: %v", err)
+		}
+
+		return decoded
+	}
+
+	t.Run("generates and logs an instance when none is set", func(t *testing.T) {
+		// GIVEN: a problem without an instance
+		buf.Reset()
+
+		problem := vital.InternalServerError("database unavailable")
+
+		// WHEN: responding with it twice
+		first := respond(t, problem)
+		second := respond(t, problem)
+
+		// THEN: each response carries its own URN and the logged instance matches the body
+		const uuidURNLength = len("urn:uuid:") + 36
+
+		if !strings.HasPrefix(first.Instance, "urn:uuid:") || len(first.Instance) != uuidURNLength {
+			t.Fatalf("expected a urn:uuid instance, got %q", first.Instance)
+		}
+
+		if first.Instance == second.Instance {
+			t.Errorf("expected distinct instances per response, got %q twice", first.Instance)
+		}
+
+		if problem.Instance != "" {
+			t.Errorf("expected the problem itself to be unmodified, got %q", problem.Instance)
+		}
+
+		firstLog, _, _ := strings.Cut(buf.String(), "\n")
+
+		var entry struct {
+			Msg      string `json:"msg"`
+			Instance string `json:"instance"`
+			Status   int    `json:"status"`
+		}
+
+		err := json.Unmarshal([]byte(firstLog), &entry)
+		if err != nil {
+			t.Fatalf("failed to decode log entry: %v", err)
+		}
+
+		if entry.Instance != first.Instance || entry.Status != http.StatusInternalServerError {
+			t.Errorf("expected logged instance %q with status 500, got %+v", first.Instance, entry)
+		}
+	})
+
+	t.Run("keeps an explicit instance", func(t *testing.T) {
+		// GIVEN: a problem with an instance
+		buf.Reset()
+
+		problem := vital.NotFound("user not found").WithInstance("/users/123")
+
+		// WHEN: responding with it
+		decoded := respond(t, problem)
+
+		// THEN: the instance is kept and nothing is logged
+		if decoded.Instance != "/users/123" {
+			t.Errorf("expected instance %q, got %q", "/users/123", decoded.Instance)
+		}
+
+		if buf.Len() > 0 {
+			t.Errorf("expected no log output, got: %s", buf.String())
+		}
+	})
+}
+
+func TestRespondProblemCtx_TitleResolver(t *testing.T) {
+	// GIVEN: a title resolver with German translations and a language-negotiated handler
+	vital.SetTitleResolver(func(status int, lang string) string {
+		if lang == "de" && status == http.StatusBadRequest {
+			return "Ungültige Anfrage"
+		}
+
+		return ""
+	})
+	t.Cleanup(func() { vital.SetTitleResolver(nil) })
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		problem        *vital.ProblemDetail
+		expectedTitle  string
+	}{
+		{
+			name:           "standard title is translated",
+			acceptLanguage: "de-DE, en;q=0.5",
+			problem:        vital.BadRequest("invalid id"),
+			expectedTitle:  "Ungültige Anfrage",
+		},
+		{
+			name:           "default language keeps the English title",
+			acceptLanguage: "en",
+			problem:        vital.BadRequest("invalid id"),
+			expectedTitle:  "Bad Request",
+		},
+		{
+			name:           "missing translation keeps the English title",
+			acceptLanguage: "de",
+			problem:        vital.NotFound("user not found"),
+			expectedTitle:  "Not Found",
+		},
+		{
+			name:           "custom title is kept",
+			acceptLanguage: "de",
+			problem:        vital.NewProblemDetail(http.StatusBadRequest, "Validation Failed"),
+			expectedTitle:  "Validation Failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := vital.Language("en", "de")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				vital.RespondProblemCtx(r.Context(), w, tt.problem)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+
+			rec := httptest.NewRecorder()
+
+			// WHEN: responding with a context-aware problem
+			handler.ServeHTTP(rec, req)
+
+			// THEN: the title is localized at write time without modifying the problem
+			var body map[string]any
+
+			err := json.Unmarshal(rec.Body.Bytes(), &body)
+			if err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+
+			if body["title"] != tt.expectedTitle {
+				t.Errorf("expected title %q, got %v", tt.expectedTitle, body["title"])
+			}
+
+			if tt.problem.Title == "Ungültige Anfrage" {
+				t.Error("expected the original problem to keep its English title")
+			}
+		})
+	}
+}
+
 func TestCommonProblemConstructors(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -316,3 +767,55 @@ func deepEqual(a, b any) bool {
 
 	return string(aJSON) == string(bJSON)
 }
+
+func TestMultiProblem(t *testing.T) {
+	// GIVEN: a batch with one success and one failure
+	results := []vital.BatchItemResult{
+		vital.BatchSuccess(0, http.StatusCreated),
+		vital.BatchFailure(1, vital.Conflict("email already exists")),
+	}
+
+	// WHEN: building and responding with the batch problem
+	recorder := httptest.NewRecorder()
+	vital.RespondProblem(recorder, vital.MultiProblem(http.StatusMultiStatus, results))
+
+	// THEN: the response carries the overall status and per-item array
+	if recorder.Code != http.StatusMultiStatus {
+		t.Errorf("expected status %d, got %d", http.StatusMultiStatus, recorder.Code)
+	}
+
+	var body struct {
+		Title  string `json:"title"`
+		Detail string `json:"detail"`
+		Items  []struct {
+			Index  int    `json:"index"`
+			Status int    `json:"status"`
+			Title  string `json:"title"`
+			Detail string `json:"detail"`
+		} `json:"items"`
+	}
+
+	err := json.Unmarshal(recorder.Body.Bytes(), &body)
+	if err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	if body.Title != "Multi-Status" || body.Detail != "1 of 2 items failed" {
+		t.Errorf("unexpected summary: title %q, detail %q", body.Title, body.Detail)
+	}
+
+	if len(body.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(body.Items))
+	}
+
+	success, failure := body.Items[0], body.Items[1]
+
+	if success.Index != 0 || success.Status != http.StatusCreated || success.Title != "Created" || success.Detail != "" {
+		t.Errorf("unexpected success item: %+v", success)
+	}
+
+	if failure.Index != 1 || failure.Status != http.StatusConflict || failure.Title != "Conflict" ||
+		failure.Detail != "email already exists" {
+		t.Errorf("unexpected failure item: %+v", failure)
+	}
+}
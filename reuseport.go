@@ -0,0 +1,29 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package vital
+
+import (
+	"fmt"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// reusePortControl sets SO_REUSEPORT on the socket before it is bound.
+func reusePortControl(_, _ string, conn syscall.RawConn) error {
+	var sockErr error
+
+	err := conn.Control(func(fd uintptr) {
+		//nolint:gosec // File descriptors fit in an int
+		sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to access socket: %w", err)
+	}
+
+	if sockErr != nil {
+		return fmt.Errorf("failed to set SO_REUSEPORT: %w", sockErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package vital
+
+import (
+	"errors"
+	"syscall"
+)
+
+// errReusePortUnsupported is returned when SO_REUSEPORT is requested on this platform.
+var errReusePortUnsupported = errors.New("SO_REUSEPORT is not supported on this platform")
+
+// reusePortControl fails, as SO_REUSEPORT is not supported on this platform.
+func reusePortControl(_, _ string, _ syscall.RawConn) error {
+	return errReusePortUnsupported
+}
@@ -0,0 +1,60 @@
+package vital
+
+import "net/http"
+
+// Router wraps an http.ServeMux to apply middleware globally and per route. Patterns use the
+// ServeMux syntax, including methods and wildcards, and r.Pattern and r.PathValue are
+// available to per-route middleware and handlers.
+type Router struct {
+	mux        *http.ServeMux
+	middleware []Middleware
+	handler    http.Handler
+}
+
+// NewRouter creates a Router backed by a new http.ServeMux.
+func NewRouter() *Router {
+	mux := http.NewServeMux()
+
+	return &Router{
+		mux:        mux,
+		middleware: nil,
+		handler:    mux,
+	}
+}
+
+// Use adds global middleware that runs for every request, including requests no route
+// matches, before any per-route middleware. Middleware runs in the order given, so the
+// first is the outermost. Call Use before the router serves requests.
+func (router *Router) Use(middleware ...Middleware) {
+	router.middleware = append(router.middleware, middleware...)
+	router.handler = applyMiddleware(router.mux, router.middleware)
+}
+
+// Handle registers the handler for the pattern, wrapped in the per-route middleware.
+// Middleware runs in the order given, inside the global middleware.
+func (router *Router) Handle(pattern string, handler http.Handler, middleware ...Middleware) {
+	router.mux.Handle(pattern, applyMiddleware(handler, middleware))
+}
+
+// HandleFunc registers the handler function for the pattern, wrapped in the per-route middleware.
+func (router *Router) HandleFunc(
+	pattern string,
+	handler func(http.ResponseWriter, *http.Request),
+	middleware ...Middleware,
+) {
+	router.Handle(pattern, http.HandlerFunc(handler), middleware...)
+}
+
+// ServeHTTP dispatches the request through the global middleware to the matching route.
+func (router *Router) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	router.handler.ServeHTTP(writer, req)
+}
+
+// applyMiddleware wraps the handler so that the first middleware is the outermost.
+func applyMiddleware(handler http.Handler, middleware []Middleware) http.Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return handler
+}
@@ -0,0 +1,91 @@
+package vital_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/monkescience/vital"
+)
+
+// recordingMiddleware appends its name and the matched pattern to calls when it runs.
+func recordingMiddleware(name string, calls *[]string) vital.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*calls = append(*calls, name+":"+r.Pattern)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestRouter(t *testing.T) {
+	var calls []string
+
+	// GIVEN: a router with global middleware and a route with its own middleware
+	router := vital.NewRouter()
+	router.Use(recordingMiddleware("global1", &calls), recordingMiddleware("global2", &calls))
+
+	router.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler:"+r.PathValue("id"))
+
+		w.WriteHeader(http.StatusOK)
+	}, recordingMiddleware("route1", &calls), recordingMiddleware("route2", &calls))
+
+	router.HandleFunc("GET /health", func(w http.ResponseWriter, _ *http.Request) {
+		calls = append(calls, "handler:health")
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedCalls  []string
+		expectedStatus int
+	}{
+		{
+			name: "global then per-route middleware with the matched pattern",
+			path: "/users/42",
+			expectedCalls: []string{
+				"global1:", "global2:",
+				"route1:GET /users/{id}", "route2:GET /users/{id}",
+				"handler:42",
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "route without per-route middleware",
+			path:           "/health",
+			expectedCalls:  []string{"global1:", "global2:", "handler:health"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unmatched path still runs global middleware",
+			path:           "/missing",
+			expectedCalls:  []string{"global1:", "global2:"},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+
+			rec := httptest.NewRecorder()
+
+			// WHEN: the request is dispatched
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			// THEN: middleware runs in order around the matched handler
+			if !slices.Equal(calls, tt.expectedCalls) {
+				t.Errorf("expected calls %v, got %v", tt.expectedCalls, calls)
+			}
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package vital
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// SchemaValidator validates JSON documents. Implement it to plug a JSON Schema library into
+// ValidateBody; vital does not depend on one.
+type SchemaValidator interface {
+	// Validate returns one PointerError per violation in the document, or none when it is valid.
+	// The document is syntactically valid JSON.
+	Validate(document []byte) []PointerError
+}
+
+// ValidateSchema returns a middleware that validates JSON request bodies against the schema
+// with the built-in validator (see CompileSchema). Like regexp.MustCompile, it panics when the
+// schema is invalid, so a broken schema fails at startup instead of on the first request. Use
+// CompileSchema with ValidateBody to handle the error instead.
+func ValidateSchema(schema []byte, opts ...DecodeOption) Middleware {
+	validator, err := CompileSchema(schema)
+	if err != nil {
+		panic(fmt.Sprintf("vital: ValidateSchema: %v", err))
+	}
+
+	return ValidateBody(validator, opts...)
+}
+
+// ValidateBody returns a middleware that reads the request body and validates it before the
+// handler runs. Bodies that are not valid JSON are rejected with 400 Bad Request and bodies with
+// violations with 422 Unprocessable Entity, listing each violation in the "errors" extension
+// (see WithPointerError). Valid bodies are passed on and can be read again by the handler.
+// The body is limited like the decoders (WithMaxBodySize, default 1MB).
+func ValidateBody(validator SchemaValidator, opts ...DecodeOption) Middleware {
+	return func(next http.Handler) http.Handler {
+		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ReadBody(r, append([]DecodeOption{WithResponseWriter(w)}, opts...)...)
+			if err != nil {
+				RespondProblem(w, ProblemFromDecodeError(err))
+
+				return
+			}
+
+			if !json.Valid(body) {
+				RespondProblem(w, BadRequest("request body is not valid JSON"))
+
+				return
+			}
+
+			violations := validator.Validate(body)
+			if len(violations) > 0 {
+				problem := UnprocessableEntity("request body does not match the schema")
+				for _, violation := range violations {
+					problem.WithPointerError(violation.Pointer, violation.Detail)
+				}
+
+				RespondProblem(w, problem)
+
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// errSchemaType is returned for a "type" keyword that is neither a string nor a list of strings.
+var errSchemaType = errors.New("type must be a string or an array of strings")
+
+// errNullSubschema is returned for a subschema that is null instead of an object.
+var errNullSubschema = errors.New("subschema must not be null")
+
+// jsonSchema is the subset of JSON Schema supported by the built-in validator.
+//
+//nolint:tagliatelle // JSON Schema keywords are camelCase
+type jsonSchema struct {
+	Type                 schemaTypes            `json:"type"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Enum                 []any                  `json:"enum"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	MinItems             *int                   `json:"minItems"`
+	MaxItems             *int                   `json:"maxItems"`
+}
+
+// schemaTypes holds the "type" keyword, which is either a single type or a list of types.
+type schemaTypes []string
+
+// UnmarshalJSON accepts a single type name or a list of type names.
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+
+	err := json.Unmarshal(data, &single)
+	if err == nil {
+		*t = schemaTypes{single}
+
+		return nil
+	}
+
+	var list []string
+
+	err = json.Unmarshal(data, &list)
+	if err != nil {
+		return errSchemaType
+	}
+
+	*t = list
+
+	return nil
+}
+
+// CompileSchema parses a JSON Schema for the built-in validator. It supports the keywords type,
+// properties, required, additionalProperties (as a boolean), items, enum, minimum, maximum,
+// minLength, maxLength, minItems, and maxItems; other keywords are ignored. Use a SchemaValidator
+// backed by a JSON Schema library with ValidateBody when more is needed.
+func CompileSchema(schema []byte) (SchemaValidator, error) {
+	var compiled jsonSchema
+
+	err := json.Unmarshal(schema, &compiled)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	err = compiled.check("")
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	return &compiled, nil
+}
+
+// check rejects null subschemas, which would otherwise be dereferenced during validation.
+func (s *jsonSchema) check(pointer string) error {
+	for _, name := range slices.Sorted(maps.Keys(s.Properties)) {
+		property := s.Properties[name]
+		propertyPointer := childPointer(childPointer(pointer, "properties"), name)
+
+		if property == nil {
+			return fmt.Errorf("%w: %s", errNullSubschema, propertyPointer)
+		}
+
+		err := property.check(propertyPointer)
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.Items != nil {
+		return s.Items.check(childPointer(pointer, "items"))
+	}
+
+	return nil
+}
+
+// Validate validates the document against the schema.
+func (s *jsonSchema) Validate(document []byte) []PointerError {
+	var value any
+
+	err := json.Unmarshal(document, &value)
+	if err != nil {
+		return []PointerError{{Pointer: "", Detail: "invalid JSON"}}
+	}
+
+	return s.validate(value, "", nil)
+}
+
+// validate appends the violations of value at pointer to violations.
+func (s *jsonSchema) validate(value any, pointer string, violations []PointerError) []PointerError {
+	if len(s.Type) > 0 && !slices.ContainsFunc(s.Type, func(typ string) bool { return matchesType(typ, value) }) {
+		detail := fmt.Sprintf("expected %s, got %s", strings.Join(s.Type, " or "), jsonTypeName(value))
+
+		return append(violations, PointerError{Pointer: pointer, Detail: detail})
+	}
+
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(allowed any) bool { return reflect.DeepEqual(allowed, value) }) {
+		violations = append(violations, PointerError{Pointer: pointer, Detail: "must be one of the allowed values"})
+	}
+
+	switch typed := value.(type) {
+	case map[string]any:
+		violations = s.validateObject(typed, pointer, violations)
+	case []any:
+		violations = s.validateArray(typed, pointer, violations)
+	case string:
+		violations = validateBounds(s.MinLength, s.MaxLength, utf8.RuneCountInString(typed), "length", pointer, violations)
+	case float64:
+		if s.Minimum != nil && typed < *s.Minimum {
+			violations = append(violations, PointerError{Pointer: pointer, Detail: fmt.Sprintf("must be >= %v", *s.Minimum)})
+		}
+
+		if s.Maximum != nil && typed > *s.Maximum {
+			violations = append(violations, PointerError{Pointer: pointer, Detail: fmt.Sprintf("must be <= %v", *s.Maximum)})
+		}
+	}
+
+	return violations
+}
+
+// validateObject validates required, declared, and undeclared properties in name order.
+func (s *jsonSchema) validateObject(object map[string]any, pointer string, violations []PointerError) []PointerError {
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			violations = append(violations, PointerError{Pointer: childPointer(pointer, name), Detail: "is required"})
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(object)) {
+		property, declared := s.Properties[name]
+
+		switch {
+		case declared:
+			violations = property.validate(object[name], childPointer(pointer, name), violations)
+		case s.AdditionalProperties != nil && !*s.AdditionalProperties:
+			violations = append(violations, PointerError{Pointer: childPointer(pointer, name), Detail: "is not allowed"})
+		}
+	}
+
+	return violations
+}
+
+// validateArray validates the number of items and each item.
+func (s *jsonSchema) validateArray(items []any, pointer string, violations []PointerError) []PointerError {
+	violations = validateBounds(s.MinItems, s.MaxItems, len(items), "number of items", pointer, violations)
+
+	if s.Items == nil {
+		return violations
+	}
+
+	for i, item := range items {
+		violations = s.Items.validate(item, childPointer(pointer, fmt.Sprint(i)), violations)
+	}
+
+	return violations
+}
+
+// validateBounds appends a violation when count is outside the optional bounds.
+func validateBounds(minimum, maximum *int, count int, what, pointer string, violations []PointerError) []PointerError {
+	if minimum != nil && count < *minimum {
+		violations = append(violations, PointerError{Pointer: pointer, Detail: fmt.Sprintf("%s must be >= %d", what, *minimum)})
+	}
+
+	if maximum != nil && count > *maximum {
+		violations = append(violations, PointerError{Pointer: pointer, Detail: fmt.Sprintf("%s must be <= %d", what, *maximum)})
+	}
+
+	return violations
+}
+
+// matchesType reports whether the decoded JSON value is of the JSON Schema type.
+func matchesType(typ string, value any) bool {
+	switch typ {
+	case "integer":
+		number, ok := value.(float64)
+
+		return ok && number == math.Trunc(number)
+	case "number":
+		_, ok := value.(float64)
+
+		return ok
+	default:
+		return jsonTypeName(value) == typ
+	}
+}
+
+// jsonTypeName returns the JSON Schema type name of a decoded JSON value.
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// childPointer appends a reference token to a JSON Pointer, escaping it per RFC 6901.
+func childPointer(pointer, token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	token = strings.ReplaceAll(token, "/", "~1")
+
+	return pointer + "/" + token
+}
@@ -0,0 +1,179 @@
+package vital_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/monkescience/vital"
+	"github.com/monkescience/vital/vitaltest"
+)
+
+const eventSchema = `{
+	"type": "object",
+	"required": ["id", "kind"],
+	"additionalProperties": false,
+	"properties": {
+		"id": {"type": "string", "minLength": 1},
+		"kind": {"enum": ["click", "view"]},
+		"count": {"type": "integer", "minimum": 0},
+		"tags": {"type": "array", "items": {"type": "string"}}
+	}
+}`
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedErrors []vital.PointerError
+	}{
+		{
+			name:           "valid payload reaches the handler with a re-readable body",
+			body:           `{"id":"evt-1","kind":"click","count":3,"tags":["a"]}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid payload lists each violation",
+			body:           `{"kind":"scroll","count":1.5,"tags":["a",2],"extra":true}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedErrors: []vital.PointerError{
+				{Pointer: "/id", Detail: "is required"},
+				{Pointer: "/count", Detail: "expected integer, got number"},
+				{Pointer: "/extra", Detail: "is not allowed"},
+				{Pointer: "/kind", Detail: "must be one of the allowed values"},
+				{Pointer: "/tags/1", Detail: "expected string, got number"},
+			},
+		},
+		{
+			name:           "malformed JSON is a bad request",
+			body:           `{"id":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: an endpoint guarded by a schema
+			var received string
+
+			handler := vital.ValidateSchema([]byte(eventSchema))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				received = string(body)
+
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			// WHEN: posting the payload
+			handler.ServeHTTP(rec, req)
+
+			// THEN: valid bodies pass through unchanged and violations are reported
+			if tt.expectedStatus == http.StatusOK {
+				if rec.Code != http.StatusOK {
+					t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+				}
+
+				if received != tt.body {
+					t.Errorf("expected handler to read %q, got %q", tt.body, received)
+				}
+
+				return
+			}
+
+			title := http.StatusText(tt.expectedStatus)
+			problem := vitaltest.AssertProblem(t, rec, tt.expectedStatus, title)
+
+			if received != "" {
+				t.Error("expected the handler not to run")
+			}
+
+			if tt.expectedErrors == nil {
+				return
+			}
+
+			var errs []vital.PointerError
+
+			for _, raw := range problem.Extensions["errors"].([]any) {
+				member := raw.(map[string]any)
+				errs = append(errs, vital.PointerError{
+					Pointer: member["pointer"].(string),
+					Detail:  member["detail"].(string),
+				})
+			}
+
+			if !reflect.DeepEqual(errs, tt.expectedErrors) {
+				t.Errorf("expected errors %+v, got %+v", tt.expectedErrors, errs)
+			}
+		})
+	}
+}
+
+// rejectAllValidator is a pluggable validator that rejects every document.
+type rejectAllValidator struct{}
+
+func (rejectAllValidator) Validate(_ []byte) []vital.PointerError {
+	return []vital.PointerError{{Pointer: "", Detail: "rejected"}}
+}
+
+func TestValidateBody_CustomValidator(t *testing.T) {
+	// GIVEN: an endpoint guarded by a custom validator
+	handler := vital.ValidateBody(rejectAllValidator{})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	rec := httptest.NewRecorder()
+
+	// WHEN: posting a payload
+	handler.ServeHTTP(rec, req)
+
+	// THEN: the validator's violations are reported
+	vitaltest.AssertProblem(t, rec, http.StatusUnprocessableEntity, "Unprocessable Entity")
+
+	if !strings.Contains(rec.Body.String(), `"detail":"rejected"`) {
+		t.Errorf("expected the custom violation, got: %s", rec.Body.String())
+	}
+}
+
+func TestValidateSchema_InvalidSchemaPanics(t *testing.T) {
+	// GIVEN: a schema with a null subschema
+	schema := []byte(`{"properties":{"a":null}}`)
+
+	defer func() {
+		// THEN: construction panics instead of failing every request later
+		if recover() == nil {
+			t.Error("expected ValidateSchema to panic for an invalid schema")
+		}
+	}()
+
+	// WHEN: building the middleware
+	vital.ValidateSchema(schema)
+}
+
+func TestCompileSchema_NullSubschema(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{name: "null property", schema: `{"properties":{"a":null}}`},
+		{name: "null nested property", schema: `{"items":{"properties":{"b":{"properties":{"c":null}}}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN: compiling a schema with a null subschema
+			_, err := vital.CompileSchema([]byte(tt.schema))
+
+			// THEN: the schema is rejected instead of panicking during validation
+			if err == nil {
+				t.Error("expected an error for a null subschema")
+			}
+		})
+	}
+}
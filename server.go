@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"runtime"
+	"sync/atomic"
 	"syscall"
 	"time"
 )
@@ -19,6 +22,7 @@ const (
 	idleTimeout            = 120 * time.Second
 	defaultSignalBuffer    = 1
 	defaultErrorBuffer     = 1
+	initialStackDumpSize   = 64 * 1024
 )
 
 type Server struct {
@@ -30,6 +34,11 @@ type Server struct {
 	certificatePath string
 	shutdownTimeout time.Duration
 	logger          *slog.Logger
+	logLabels       []any
+	listenerAddr    atomic.Pointer[net.Addr]
+	dumpOnQuit      bool
+	reusePort       bool
+	signals         <-chan os.Signal
 }
 
 // ServerOption is a functional option for configuring a Server.
@@ -88,6 +97,81 @@ func WithLogger(logger *slog.Logger) ServerOption {
 	}
 }
 
+// WithBaseContext sets the function that returns the base context for incoming requests.
+// Use this to thread a root context, such as a shutdown-aware one, into every request.
+func WithBaseContext(baseContext func(net.Listener) context.Context) ServerOption {
+	return func(s *Server) {
+		s.BaseContext = baseContext
+	}
+}
+
+// WithConnContext sets the function that modifies the context used for a new connection.
+// Use this to attach connection-scoped values such as a connection ID.
+func WithConnContext(connContext func(context.Context, net.Conn) context.Context) ServerOption {
+	return func(s *Server) {
+		s.ConnContext = connContext
+	}
+}
+
+// WithDefaultHeaders sets response headers on every response. The headers are set before
+// the handler runs, so handlers can override or delete them.
+func WithDefaultHeaders(headers map[string]string) ServerOption {
+	defaults := make(map[string]string, len(headers))
+	for name, value := range headers {
+		defaults[http.CanonicalHeaderKey(name)] = value
+	}
+
+	return func(s *Server) {
+		next := s.Handler
+
+		s.Handler = http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+			header := writer.Header()
+			for name, value := range defaults {
+				header.Set(name, value)
+			}
+
+			next.ServeHTTP(writer, req)
+		})
+	}
+}
+
+// WithStackDumpOnQuit makes Run log the stacks of all goroutines at error level when it
+// receives SIGQUIT, then keep serving, which helps debug stuck requests and shutdowns.
+// Without it, SIGQUIT keeps the Go runtime default of dumping stacks and exiting.
+func WithStackDumpOnQuit() ServerOption {
+	return func(s *Server) {
+		s.dumpOnQuit = true
+	}
+}
+
+// WithSignalChannel makes Run read signals from signals instead of subscribing to the
+// process signals, for example to drive shutdown from tests or an embedding supervisor.
+func WithSignalChannel(signals <-chan os.Signal) ServerOption {
+	return func(s *Server) {
+		s.signals = signals
+	}
+}
+
+// WithLogLabels adds attributes, such as region or cluster, to every log the server emits,
+// including startup, shutdown, and errors logged by the underlying http.Server. The labels
+// are bound to the logger when the server is created, regardless of the order of WithLogger.
+func WithLogLabels(attrs ...slog.Attr) ServerOption {
+	return func(s *Server) {
+		for _, attr := range attrs {
+			s.logLabels = append(s.logLabels, attr)
+		}
+	}
+}
+
+// WithReusePort sets SO_REUSEPORT on the listener, so several processes can bind the same
+// port, for example to hand over traffic between the old and new process during a deploy.
+// Binding fails on platforms without SO_REUSEPORT.
+func WithReusePort() ServerOption {
+	return func(s *Server) {
+		s.reusePort = true
+	}
+}
+
 // NewServer creates a new Server with the provided handler and options.
 func NewServer(handler http.Handler, opts ...ServerOption) *Server {
 	// Use default logger
@@ -114,85 +198,278 @@ func NewServer(handler http.Handler, opts ...ServerOption) *Server {
 		opt(server)
 	}
 
+	if len(server.logLabels) > 0 {
+		server.logger = server.logger.With(server.logLabels...)
+		server.ErrorLog = slog.NewLogLogger(server.logger.Handler(), slog.LevelError)
+	}
+
 	return server
 }
 
 // Run starts the server and blocks until a termination signal is received.
+// SIGINT and SIGTERM drain in-flight requests within the shutdown timeout; with
+// WithStackDumpOnQuit, SIGQUIT logs all goroutine stacks and the server keeps running.
+// The listener is bound before Run waits for signals, so a shutdown never races the
+// startup, and Run returns once Stop is called directly.
 func (server *Server) Run() {
-	// Channel to listen for errors from the server
-	serverErrors := make(chan error, defaultErrorBuffer)
+	signals, stopSignals := server.subscribeSignals()
+	defer stopSignals()
 
-	// Start server in a goroutine
-	go func() {
-		err := server.Start()
-		if err != nil && !errors.Is(err, http.ErrServerClosed) {
-			serverErrors <- err
-		}
-	}()
-
-	// Channel to listen for interrupt signals
-	shutdown := make(chan os.Signal, defaultSignalBuffer)
-
-	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
-
-	// Block until we receive a signal or an error
-	select {
-	case err := <-serverErrors:
-		signal.Stop(shutdown)
+	listener, err := server.listen()
+	if err != nil {
 		server.logger.Error(
 			"server error",
 			slog.Any("err", err),
 		)
 		os.Exit(1)
+	}
 
-	case sig := <-shutdown:
-		signal.Stop(shutdown)
-		server.logger.Info(
-			"received shutdown signal",
-			slog.String("signal", sig.String()),
-		)
+	server.setListenerAddr(listener.Addr())
+	server.logListening(listener.Addr())
+
+	// Channel to receive the result of serving, nil once the server is shut down
+	serverErrors := make(chan error, defaultErrorBuffer)
+
+	go func() {
+		serverErrors <- server.serve(listener)
+	}()
+
+	// Block until we receive a termination signal or the server stops
+	for {
+		select {
+		case err := <-serverErrors:
+			if err == nil {
+				// Stop was called directly
+				return
+			}
 
-		err := server.Stop()
-		if err != nil {
 			server.logger.Error(
-				"failed to stop server gracefully",
+				"server error",
 				slog.Any("err", err),
 			)
 			os.Exit(1)
+
+		case sig := <-signals:
+			if sig == syscall.SIGQUIT && server.dumpOnQuit {
+				server.logGoroutineStacks()
+
+				continue
+			}
+
+			server.logger.Info(
+				"received shutdown signal",
+				slog.String("signal", sig.String()),
+			)
+
+			err := server.Stop()
+			if err != nil {
+				server.logger.Error(
+					"failed to stop server gracefully",
+					slog.Any("err", err),
+				)
+				os.Exit(1)
+			}
+
+			server.logger.Info("server stopped gracefully")
+
+			return
 		}
+	}
+}
+
+// subscribeSignals returns the channel Run reads signals from and a function to unsubscribe.
+// SIGQUIT is only subscribed to with WithStackDumpOnQuit, so the runtime default applies otherwise.
+func (server *Server) subscribeSignals() (<-chan os.Signal, func()) {
+	if server.signals != nil {
+		return server.signals, func() {}
+	}
 
-		server.logger.Info("server stopped gracefully")
+	signals := []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+	if server.dumpOnQuit {
+		signals = append(signals, syscall.SIGQUIT)
 	}
+
+	notify := make(chan os.Signal, defaultSignalBuffer)
+	signal.Notify(notify, signals...)
+
+	return notify, func() { signal.Stop(notify) }
+}
+
+// logGoroutineStacks logs the stacks of all goroutines at error level.
+func (server *Server) logGoroutineStacks() {
+	buf := make([]byte, initialStackDumpSize)
+
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+
+			break
+		}
+
+		buf = make([]byte, 2*len(buf))
+	}
+
+	server.logger.Error(
+		"received SIGQUIT, dumping goroutine stacks",
+		slog.String("stacks", string(buf)),
+	)
 }
 
 // Start begins listening and serving HTTP or HTTPS requests.
 // It blocks until the server stops or encounters an error.
 func (server *Server) Start() error {
-	server.logger.Info(
-		"starting server",
-		slog.Int("port", server.port),
-		slog.Bool("tls", server.useTLS),
-	)
+	listener, err := server.listen()
+	if err != nil {
+		return fmt.Errorf("failed to start server: %w", err)
+	}
 
-	var err error
-	if server.useTLS {
-		err = server.ListenAndServeTLS(server.certificatePath, server.keyPath)
-		if err != nil {
+	server.setListenerAddr(listener.Addr())
+	server.logListening(listener.Addr())
+
+	err = server.serveListener(listener)
+	if err != nil {
+		if server.useTLS {
 			return fmt.Errorf("failed to start TLS server: %w", err)
 		}
-	} else {
-		err = server.ListenAndServe()
+
+		return fmt.Errorf("failed to start HTTP server: %w", err)
+	}
+
+	return nil
+}
+
+// logListening logs the address the server is bound to, including the port the operating
+// system picked when the server was configured with WithPort(0).
+func (server *Server) logListening(addr net.Addr) {
+	attrs := []any{
+		slog.String("addr", addr.String()),
+		slog.Bool("tls", server.useTLS),
+	}
+
+	if tcpAddr, ok := addr.(*net.TCPAddr); ok {
+		attrs = append(attrs, slog.Int("port", tcpAddr.Port))
+	}
+
+	server.logger.Info("server listening", attrs...)
+}
+
+// StartContext starts serving in the background and returns once the listener is set up.
+// The ready channel is closed as soon as the listener is bound, so callers can connect
+// without sleeping. Fatal errors, including a failure to bind, are sent on the error channel.
+// When ctx is cancelled the server is stopped gracefully. The error channel is closed once
+// the server has stopped.
+func (server *Server) StartContext(ctx context.Context) (<-chan struct{}, <-chan error) {
+	ready := make(chan struct{})
+	errs := make(chan error, defaultErrorBuffer)
+
+	go func() {
+		defer close(errs)
+
+		listener, err := server.listen()
 		if err != nil {
-			return fmt.Errorf("failed to start HTTP server: %w", err)
+			errs <- err
+
+			return
+		}
+
+		server.setListenerAddr(listener.Addr())
+		server.logListening(listener.Addr())
+
+		close(ready)
+
+		serveErr := make(chan error, defaultErrorBuffer)
+
+		go func() {
+			serveErr <- server.serve(listener)
+		}()
+
+		select {
+		case err := <-serveErr:
+			if err != nil {
+				errs <- err
+			}
+		case <-ctx.Done():
+			err := server.Stop()
+
+			<-serveErr
+
+			if err != nil {
+				errs <- err
+			}
+		}
+	}()
+
+	return ready, errs
+}
+
+// ListenerAddr returns the address the server is listening on once Start, Run, or StartContext
+// has bound the listener, or nil before. Use it to find the port chosen with WithPort(0). It is
+// safe to call concurrently with them.
+func (server *Server) ListenerAddr() net.Addr {
+	addr := server.listenerAddr.Load()
+	if addr == nil {
+		return nil
+	}
+
+	return *addr
+}
+
+// setListenerAddr records the address the server is listening on for ListenerAddr.
+func (server *Server) setListenerAddr(addr net.Addr) {
+	server.listenerAddr.Store(&addr)
+}
+
+// listen binds the configured address, defaulting to the standard HTTP or HTTPS port.
+func (server *Server) listen() (net.Listener, error) {
+	addr := server.Addr
+	if addr == "" {
+		addr = ":http"
+		if server.useTLS {
+			addr = ":https"
 		}
 	}
 
+	//nolint:exhaustruct // Only setting Control, others use sensible defaults
+	config := net.ListenConfig{}
+	if server.reusePort {
+		config.Control = reusePortControl
+	}
+
+	listener, err := config.Listen(context.Background(), "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	return listener, nil
+}
+
+// serve serves HTTP or HTTPS requests on the listener until the server stops.
+// It returns nil once the server is shut down.
+func (server *Server) serve(listener net.Listener) error {
+	err := server.serveListener(listener)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve: %w", err)
+	}
+
 	return nil
 }
 
+// serveListener serves HTTP or HTTPS requests on the listener, returning the error of
+// http.Server.Serve or ServeTLS, which is http.ErrServerClosed after a shutdown.
+func (server *Server) serveListener(listener net.Listener) error {
+	if server.useTLS {
+		return server.ServeTLS(listener, server.certificatePath, server.keyPath) //nolint:wrapcheck // Callers add context
+	}
+
+	return server.Serve(listener) //nolint:wrapcheck // Callers add context
+}
+
 // Stop gracefully shuts down the server with the configured shutdown timeout.
+// Stopping a server that has not started yet is safe: it will not start serving afterwards.
 func (server *Server) Stop() error {
 	ctx, cancel := context.WithTimeout(context.Background(), server.shutdownTimeout)
+	defer cancel()
 
 	server.logger.Info(
 		"stopping server",
@@ -200,9 +477,6 @@ func (server *Server) Stop() error {
 	)
 
 	err := server.Shutdown(ctx)
-
-	cancel()
-
 	if err != nil {
 		return fmt.Errorf("shutdown failed: %w", err)
 	}
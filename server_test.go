@@ -1,14 +1,22 @@
 package vital_test
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
 	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
@@ -192,6 +200,103 @@ func TestServer_HTTP(t *testing.T) {
 	})
 }
 
+func TestServer_Contexts(t *testing.T) {
+	type contextKey string
+
+	// GIVEN: a server with base and connection context functions
+	var connections atomic.Int32
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "%v|%v", r.Context().Value(contextKey("root")), r.Context().Value(contextKey("conn")))
+	})
+
+	server := vital.NewServer(
+		handler,
+		vital.WithLogger(slog.New(slog.DiscardHandler)),
+		vital.WithBaseContext(func(net.Listener) context.Context {
+			return context.WithValue(context.Background(), contextKey("root"), "base")
+		}),
+		vital.WithConnContext(func(ctx context.Context, _ net.Conn) context.Context {
+			return context.WithValue(ctx, contextKey("conn"), connections.Add(1))
+		}),
+	)
+
+	testServer := httptest.NewUnstartedServer(handler)
+	testServer.Config = server.Server
+	testServer.Start()
+
+	defer testServer.Close()
+
+	// WHEN: making a request
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, testServer.URL, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	resp, err := testServer.Client().Do(req)
+	if err != nil {
+		t.Fatalf("failed to make HTTP request: %v", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read response body: %v", err)
+	}
+
+	// THEN: the handler should see both context values
+	if string(body) != "base|1" {
+		t.Errorf("expected body %q, got %q", "base|1", string(body))
+	}
+}
+
+func TestServer_DefaultHeaders(t *testing.T) {
+	// GIVEN: a server with default headers and a handler overriding one of them
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/override" {
+			w.Header().Set("Cache-Control", "max-age=60")
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := vital.NewServer(
+		handler,
+		vital.WithLogger(slog.New(slog.DiscardHandler)),
+		vital.WithDefaultHeaders(map[string]string{
+			"x-service":     "payments",
+			"Cache-Control": "no-store",
+		}),
+	)
+
+	tests := []struct {
+		path                 string
+		expectedCacheControl string
+	}{
+		{path: "/", expectedCacheControl: "no-store"},
+		{path: "/override", expectedCacheControl: "max-age=60"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			// WHEN: serving a request
+			server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			// THEN: default headers are present unless the handler overrides them
+			if got := rec.Header().Get("X-Service"); got != "payments" {
+				t.Errorf("expected X-Service %q, got %q", "payments", got)
+			}
+
+			if got := rec.Header().Get("Cache-Control"); got != tt.expectedCacheControl {
+				t.Errorf("expected Cache-Control %q, got %q", tt.expectedCacheControl, got)
+			}
+		})
+	}
+}
+
 func TestServer_Stop(t *testing.T) {
 	t.Run("gracefully shuts down server", func(t *testing.T) {
 		// GIVEN: a running HTTP server
@@ -263,6 +368,376 @@ func TestServer_Stop(t *testing.T) {
 	})
 }
 
+func TestServer_Run_StopImmediately(t *testing.T) {
+	tests := []struct {
+		name string
+		stop func(server *vital.Server, signals chan<- os.Signal)
+	}{
+		{
+			name: "direct Stop",
+			stop: func(server *vital.Server, _ chan<- os.Signal) { _ = server.Stop() },
+		},
+		{
+			name: "termination signal",
+			stop: func(_ *vital.Server, signals chan<- os.Signal) { signals <- syscall.SIGTERM },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a server started with Run
+			signals := make(chan os.Signal, 1)
+
+			server := vital.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				}),
+				vital.WithPort(getAvailablePort(t)),
+				vital.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+				vital.WithSignalChannel(signals),
+			)
+
+			done := make(chan struct{})
+
+			go func() {
+				defer close(done)
+
+				server.Run()
+			}()
+
+			// WHEN: stopping the server right after Run begins, before it may be serving
+			tt.stop(server, signals)
+
+			// THEN: Run returns without hanging
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("expected Run to return after an immediate stop")
+			}
+		})
+	}
+}
+
+func TestServer_Run_Signals(t *testing.T) {
+	// GIVEN: a server reading signals from an injected channel with stack dumps enabled
+	var buf bytes.Buffer
+
+	signals := make(chan os.Signal, 1)
+	port := getAvailablePort(t)
+
+	server := vital.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+		vital.WithPort(port),
+		vital.WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))),
+		vital.WithSignalChannel(signals),
+		vital.WithStackDumpOnQuit(),
+	)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		server.Run()
+	}()
+
+	serverURL := fmt.Sprintf("http://localhost:%d", port)
+	waitForServer(t, serverURL)
+
+	// WHEN: SIGQUIT is received
+	signals <- syscall.SIGQUIT
+
+	// THEN: the server keeps serving
+	waitForServer(t, serverURL)
+
+	// WHEN: SIGTERM is received
+	signals <- syscall.SIGTERM
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Run to return after SIGTERM")
+	}
+
+	// THEN: the goroutine dump was logged at error level and the server drained
+	output := buf.String()
+
+	var dump string
+
+	for line := range strings.SplitSeq(output, "\n") {
+		if strings.Contains(line, "dumping goroutine stacks") {
+			dump = line
+		}
+	}
+
+	if !strings.Contains(dump, `"level":"ERROR"`) || !strings.Contains(dump, "goroutine ") {
+		t.Errorf("expected goroutine dump at error level, got: %s", output)
+	}
+
+	if !strings.Contains(output, "server stopped gracefully") {
+		t.Errorf("expected graceful shutdown, got: %s", output)
+	}
+}
+
+func TestServer_StartContext(t *testing.T) {
+	t.Run("signals readiness and stops on cancellation", func(t *testing.T) {
+		// GIVEN: a server on a random port started with a cancellable context
+		server := vital.NewServer(
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}),
+			vital.WithPort(0),
+			vital.WithLogger(slog.New(slog.DiscardHandler)),
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ready, errs := server.StartContext(ctx)
+
+		// WHEN: waiting for the ready signal and connecting
+		select {
+		case <-ready:
+		case err := <-errs:
+			t.Fatalf("server failed to start: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for server readiness")
+		}
+
+		url := "http://" + server.ListenerAddr().String()
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed right after readiness: %v", err)
+		}
+
+		_ = resp.Body.Close()
+
+		// THEN: the request succeeds and cancellation shuts the server down cleanly
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+
+		cancel()
+
+		select {
+		case err, open := <-errs:
+			if open {
+				t.Errorf("expected clean shutdown, got %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for server to stop")
+		}
+	})
+
+	t.Run("reports bind errors", func(t *testing.T) {
+		// GIVEN: a port that is already in use
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+
+		defer func() { _ = listener.Close() }()
+
+		server := vital.NewServer(
+			http.NotFoundHandler(),
+			vital.WithLogger(slog.New(slog.DiscardHandler)),
+		)
+		server.Addr = listener.Addr().String()
+
+		// WHEN: starting the server
+		ready, errs := server.StartContext(context.Background())
+
+		// THEN: the error is reported and readiness is never signalled
+		select {
+		case err := <-errs:
+			if err == nil {
+				t.Error("expected bind error")
+			}
+		case <-ready:
+			t.Fatal("expected no readiness signal")
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for bind error")
+		}
+	})
+}
+
+func TestServer_LogsListeningAddress(t *testing.T) {
+	// GIVEN: a server on a port chosen by the operating system
+	var buf bytes.Buffer
+
+	server := vital.NewServer(
+		http.NotFoundHandler(),
+		vital.WithPort(0),
+		vital.WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// WHEN: the server has bound its listener
+	ready, errs := server.StartContext(ctx)
+
+	select {
+	case <-ready:
+	case err := <-errs:
+		t.Fatalf("server failed to start: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for server readiness")
+	}
+
+	cancel()
+
+	select {
+	case <-errs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for server to stop")
+	}
+
+	// THEN: the resolved address and its non-zero port are logged
+	var entry struct {
+		Msg  string `json:"msg"`
+		Addr string `json:"addr"`
+		Port int    `json:"port"`
+	}
+
+	for line := range strings.SplitSeq(buf.String(), "\n") {
+		if strings.Contains(line, "server listening") {
+			err := json.Unmarshal([]byte(line), &entry)
+			if err != nil {
+				t.Fatalf("failed to decode log line: %v", err)
+			}
+		}
+	}
+
+	if entry.Msg != "server listening" {
+		t.Fatalf("expected a server listening log entry, got: %s", buf.String())
+	}
+
+	if entry.Port == 0 {
+		t.Errorf("expected a non-zero port, got: %s", buf.String())
+	}
+
+	if entry.Addr != server.ListenerAddr().String() {
+		t.Errorf("expected addr %q, got %q", server.ListenerAddr().String(), entry.Addr)
+	}
+}
+
+func TestServer_ReusePort(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("SO_REUSEPORT behavior is only tested on Linux")
+	}
+
+	// GIVEN: two servers on the same port with SO_REUSEPORT
+	port := getAvailablePort(t)
+
+	newServer := func(opts ...vital.ServerOption) *vital.Server {
+		return vital.NewServer(
+			http.NotFoundHandler(),
+			append([]vital.ServerOption{
+				vital.WithPort(port),
+				vital.WithLogger(slog.New(slog.DiscardHandler)),
+			}, opts...)...,
+		)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// WHEN: starting both servers
+	for i := range 2 {
+		ready, errs := newServer(vital.WithReusePort()).StartContext(ctx)
+
+		// THEN: both bind the port
+		select {
+		case <-ready:
+		case err := <-errs:
+			t.Fatalf("server %d failed to bind: %v", i+1, err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for server %d", i+1)
+		}
+	}
+
+	// THEN: a server without the option cannot bind the same port
+	ready, errs := newServer().StartContext(ctx)
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("expected bind error without SO_REUSEPORT")
+		}
+	case <-ready:
+		t.Fatal("expected the port to be unavailable without SO_REUSEPORT")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for bind error")
+	}
+}
+
+func TestServer_LogLabels(t *testing.T) {
+	// GIVEN: a server with log labels configured before the logger
+	var buf bytes.Buffer
+
+	server := vital.NewServer(
+		http.NotFoundHandler(),
+		vital.WithPort(0),
+		vital.WithLogLabels(slog.String("region", "eu-west-1"), slog.String("cluster", "blue")),
+		vital.WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// WHEN: the server starts and stops
+	ready, errs := server.StartContext(ctx)
+
+	select {
+	case <-ready:
+	case err := <-errs:
+		t.Fatalf("server failed to start: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for server readiness")
+	}
+
+	cancel()
+
+	select {
+	case <-errs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for server to stop")
+	}
+
+	// THEN: the startup and shutdown logs carry the labels
+	messages := make(map[string]bool)
+
+	for line := range strings.SplitSeq(strings.TrimSpace(buf.String()), "\n") {
+		var entry struct {
+			Msg     string `json:"msg"`
+			Region  string `json:"region"`
+			Cluster string `json:"cluster"`
+		}
+
+		err := json.Unmarshal([]byte(line), &entry)
+		if err != nil {
+			t.Fatalf("failed to decode log line: %v", err)
+		}
+
+		if entry.Region != "eu-west-1" || entry.Cluster != "blue" {
+			t.Errorf("expected labels on %q, got: %s", entry.Msg, line)
+		}
+
+		messages[entry.Msg] = true
+	}
+
+	if !messages["server listening"] || !messages["stopping server"] {
+		t.Errorf("expected startup and shutdown logs, got: %s", buf.String())
+	}
+}
+
 func TestServerIntegration_HTTP(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test in short mode")
@@ -503,3 +978,94 @@ func waitForServer(t *testing.T, url string) {
 
 	t.Fatalf("server did not become ready at %s", url)
 }
+
+func TestServer_ListenerAddrDuringRun(t *testing.T) {
+	// GIVEN: a server on a port chosen by the OS, run with a signal channel
+	signals := make(chan os.Signal, 1)
+
+	server := vital.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+		vital.WithPort(0),
+		vital.WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))),
+		vital.WithSignalChannel(signals),
+	)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		server.Run()
+	}()
+
+	// WHEN: polling the listener address while Run starts
+	deadline := time.Now().Add(5 * time.Second)
+
+	addr := server.ListenerAddr()
+	for addr == nil && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+
+		addr = server.ListenerAddr()
+	}
+
+	// THEN: the address becomes available and serves requests
+	if addr == nil {
+		t.Fatal("expected a listener address after Run started")
+	}
+
+	waitForServer(t, "http://"+addr.String())
+
+	signals <- syscall.SIGTERM
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Run to return after SIGTERM")
+	}
+}
+
+func TestServer_ListenerAddrAfterStart(t *testing.T) {
+	// GIVEN: a server on a port chosen by the OS
+	server := vital.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+		vital.WithPort(0),
+		vital.WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))),
+	)
+
+	startErr := make(chan error, 1)
+
+	go func() {
+		startErr <- server.Start()
+	}()
+
+	// WHEN: polling the listener address while Start serves
+	deadline := time.Now().Add(5 * time.Second)
+
+	addr := server.ListenerAddr()
+	for addr == nil && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+
+		addr = server.ListenerAddr()
+	}
+
+	// THEN: the address becomes available, serves requests, and Start reports the shutdown
+	if addr == nil {
+		t.Fatal("expected a listener address after Start")
+	}
+
+	waitForServer(t, "http://"+addr.String())
+
+	err := server.Stop()
+	if err != nil {
+		t.Fatalf("failed to stop server: %v", err)
+	}
+
+	err = <-startErr
+	if !errors.Is(err, http.ErrServerClosed) {
+		t.Errorf("expected http.ErrServerClosed from Start, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package vital
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const defaultTenantHeader = "X-Tenant-ID"
+
+// TenantIDKey is the context key for the resolved tenant ID.
+//
+//nolint:gochecknoglobals // Global key is required for middleware integration
+var TenantIDKey = ContextKey{Name: "tenant_id"}
+
+// TenantOption configures the TenantID middleware.
+type TenantOption func(*tenantConfig)
+
+// tenantConfig holds configuration for the TenantID middleware.
+type tenantConfig struct {
+	header     string
+	baseDomain string
+	required   bool
+}
+
+// WithTenantHeader sets the header the tenant ID is read from (default "X-Tenant-ID").
+func WithTenantHeader(name string) TenantOption {
+	return func(c *tenantConfig) {
+		c.header = name
+	}
+}
+
+// WithTenantBaseDomain enables tenant resolution from the subdomain of the given base domain.
+// For base domain "app.com", a request to "acme.app.com" resolves to tenant "acme".
+func WithTenantBaseDomain(domain string) TenantOption {
+	return func(c *tenantConfig) {
+		c.baseDomain = strings.ToLower(strings.Trim(domain, "."))
+	}
+}
+
+// WithRequireTenant rejects requests without a resolvable tenant with a 403 Forbidden ProblemDetail.
+func WithRequireTenant() TenantOption {
+	return func(c *tenantConfig) {
+		c.required = true
+	}
+}
+
+// TenantID returns a middleware that resolves the tenant of each request and stores it in the
+// request context under TenantIDKey. The tenant header is checked first, then the subdomain
+// if a base domain is configured. Register TenantIDKey with a ContextHandler to log it.
+func TenantID(opts ...TenantOption) Middleware {
+	cfg := &tenantConfig{
+		header: defaultTenantHeader,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenantID := resolveTenantID(r, cfg)
+
+			if tenantID == "" {
+				if cfg.required {
+					RespondProblem(w, Forbidden("tenant could not be resolved"))
+
+					return
+				}
+
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), TenantIDKey, tenantID)))
+		})
+	}
+}
+
+// resolveTenantID resolves the tenant from the header, falling back to the subdomain.
+func resolveTenantID(r *http.Request, cfg *tenantConfig) string {
+	if tenantID := strings.TrimSpace(r.Header.Get(cfg.header)); tenantID != "" {
+		return tenantID
+	}
+
+	if cfg.baseDomain == "" {
+		return ""
+	}
+
+	host := r.Host
+
+	hostname, _, err := net.SplitHostPort(host)
+	if err == nil {
+		host = hostname
+	}
+
+	host = strings.ToLower(host)
+
+	subdomain, found := strings.CutSuffix(host, "."+cfg.baseDomain)
+	if !found || subdomain == "" || strings.Contains(subdomain, ".") {
+		return ""
+	}
+
+	return subdomain
+}
+
+// GetTenantID retrieves the tenant ID from the request context.
+func GetTenantID(ctx context.Context) string {
+	if tenantID, ok := ctx.Value(TenantIDKey).(string); ok {
+		return tenantID
+	}
+
+	return ""
+}
@@ -0,0 +1,120 @@
+package vital_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/monkescience/vital"
+)
+
+func TestTenantID(t *testing.T) {
+	var resolved string
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resolved = vital.GetTenantID(r.Context())
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name           string
+		opts           []vital.TenantOption
+		host           string
+		header         string
+		expectedTenant string
+		expectedStatus int
+	}{
+		{
+			name:           "header-based tenant",
+			opts:           []vital.TenantOption{vital.WithTenantBaseDomain("app.com")},
+			host:           "other.app.com",
+			header:         "acme",
+			expectedTenant: "acme",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "subdomain-based tenant",
+			opts:           []vital.TenantOption{vital.WithTenantBaseDomain("app.com")},
+			host:           "acme.app.com:8443",
+			expectedTenant: "acme",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "nested subdomain is not a tenant",
+			opts:           []vital.TenantOption{vital.WithTenantBaseDomain("app.com")},
+			host:           "api.acme.app.com",
+			expectedTenant: "",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing tenant is allowed by default",
+			host:           "app.com",
+			expectedTenant: "",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing tenant is rejected when required",
+			opts:           []vital.TenantOption{vital.WithTenantBaseDomain("app.com"), vital.WithRequireTenant()},
+			host:           "app.com",
+			expectedTenant: "",
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a request with the given host and tenant header
+			resolved = ""
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Host = tt.host
+
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-ID", tt.header)
+			}
+
+			rec := httptest.NewRecorder()
+
+			// WHEN: the tenant middleware processes the request
+			vital.TenantID(tt.opts...)(handler).ServeHTTP(rec, req)
+
+			// THEN: the tenant should be resolved as expected
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+
+			if resolved != tt.expectedTenant {
+				t.Errorf("expected tenant %q, got %q", tt.expectedTenant, resolved)
+			}
+		})
+	}
+}
+
+func TestTenantID_Logging(t *testing.T) {
+	// GIVEN: a context handler with TenantIDKey registered
+	var buf bytes.Buffer
+
+	logger := slog.New(vital.NewContextHandler(
+		slog.NewJSONHandler(&buf, nil),
+		vital.WithContextKeys(vital.TenantIDKey),
+	))
+
+	handler := vital.TenantID(vital.WithTenantHeader("X-Org"))(vital.RequestLogger(logger)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Org", "acme")
+
+	// WHEN: the handler processes the request
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// THEN: the tenant should appear in the access log
+	if !strings.Contains(buf.String(), `"tenant_id":"acme"`) {
+		t.Errorf("expected tenant_id in log, got: %s", buf.String())
+	}
+}
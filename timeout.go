@@ -69,6 +69,32 @@ func Timeout(duration time.Duration) Middleware {
 	}
 }
 
+// SoftDeadline returns a middleware that observes handlers running longer than d without aborting them.
+// After the handler completes, onExceed is called with the request and the elapsed time if it exceeded d.
+// Unlike Timeout, the request context is left untouched, so the handler always runs to completion.
+// Use the hook to record SLA metrics or alerts.
+//
+// A duration of 0 or negative disables the observation (passthrough).
+func SoftDeadline(d time.Duration, onExceed func(r *http.Request, elapsed time.Duration)) Middleware {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 || onExceed == nil {
+			return next
+		}
+
+		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			next.ServeHTTP(w, r)
+
+			elapsed := time.Since(start)
+			if elapsed > d {
+				onExceed(r, elapsed)
+			}
+		})
+	}
+}
+
 // timeoutResponseWriter wraps http.ResponseWriter to track if headers have been sent.
 type timeoutResponseWriter struct {
 	http.ResponseWriter
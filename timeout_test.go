@@ -361,3 +361,73 @@ func TestTimeout_ZeroAndNegativeEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestSoftDeadline(t *testing.T) {
+	tests := []struct {
+		name         string
+		handlerDelay time.Duration
+		expectHook   bool
+	}{
+		{
+			name:         "fast handler does not trigger the hook",
+			handlerDelay: 0,
+			expectHook:   false,
+		},
+		{
+			name:         "slow handler triggers the hook",
+			handlerDelay: 50 * time.Millisecond,
+			expectHook:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a soft deadline of 20ms wrapping a handler with a delay
+			var (
+				called  bool
+				path    string
+				elapsed time.Duration
+			)
+
+			handler := vital.SoftDeadline(20*time.Millisecond, func(r *http.Request, d time.Duration) {
+				called = true
+				path = r.URL.Path
+				elapsed = d
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(tt.handlerDelay)
+
+				if r.Context().Err() != nil {
+					t.Error("expected request context to stay active")
+				}
+
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			rec := httptest.NewRecorder()
+
+			// WHEN: the request is processed
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+			// THEN: the handler completes and the hook fires only when the deadline was exceeded
+			if rec.Code != http.StatusOK {
+				t.Errorf("expected status %d, got %d", http.StatusOK, rec.Code)
+			}
+
+			if called != tt.expectHook {
+				t.Fatalf("expected hook called=%v, got %v", tt.expectHook, called)
+			}
+
+			if !tt.expectHook {
+				return
+			}
+
+			if path != "/slow" {
+				t.Errorf("expected hook to receive the request, got path %q", path)
+			}
+
+			if elapsed < tt.handlerDelay {
+				t.Errorf("expected elapsed >= %v, got %v", tt.handlerDelay, elapsed)
+			}
+		})
+	}
+}
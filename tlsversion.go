@@ -0,0 +1,93 @@
+package vital
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// errInvalidTLSVersion is returned for a proxy-reported TLS version that cannot be parsed.
+var errInvalidTLSVersion = errors.New("invalid TLS version")
+
+// RequireTLSVersion returns a middleware that rejects requests negotiated with a TLS version
+// below minVersion (e.g. 1.2) with a 400 Bad Request ProblemDetail.
+//
+// For direct TLS connections the negotiated version from r.TLS is used. Otherwise the version
+// reported by a terminating proxy is read from minHeader (e.g. "X-Forwarded-TLS-Version"),
+// accepting values such as "1.2", "TLSv1.2", or "TLS 1.2"; unparseable values are rejected.
+// Requests without TLS information pass through, so combine this with HTTPS enforcement.
+// Only trust the header when the service runs behind a proxy that sets it.
+func RequireTLSVersion(minHeader string, minVersion float64) Middleware {
+	return func(next http.Handler) http.Handler {
+		//nolint:varnamelen // w and r are conventional names for http.ResponseWriter and *http.Request
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			version, known, err := requestTLSVersion(r, minHeader)
+			if err != nil {
+				RespondProblem(w, BadRequest(err.Error()))
+
+				return
+			}
+
+			if known && version < minVersion {
+				RespondProblem(w, BadRequest(fmt.Sprintf(
+					"TLS version %s is below the minimum of %s",
+					formatTLSVersion(version),
+					formatTLSVersion(minVersion),
+				)))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requestTLSVersion returns the TLS version of the request and whether it is known.
+func requestTLSVersion(r *http.Request, header string) (float64, bool, error) {
+	if r.TLS != nil {
+		return tlsVersionNumber(r.TLS.Version), true, nil
+	}
+
+	if header == "" {
+		return 0, false, nil
+	}
+
+	value := strings.TrimSpace(r.Header.Get(header))
+	if value == "" {
+		return 0, false, nil
+	}
+
+	number := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(strings.ToUpper(value), "TLS"), "V"))
+
+	version, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("%w %q", errInvalidTLSVersion, value)
+	}
+
+	return version, true, nil
+}
+
+// tlsVersionNumber converts a crypto/tls version constant to its decimal form.
+func tlsVersionNumber(version uint16) float64 {
+	switch version {
+	case tls.VersionTLS10:
+		return 1.0
+	case tls.VersionTLS11:
+		return 1.1
+	case tls.VersionTLS12:
+		return 1.2
+	case tls.VersionTLS13:
+		return 1.3
+	default:
+		return 0
+	}
+}
+
+// formatTLSVersion formats a decimal TLS version such as 1.2.
+func formatTLSVersion(version float64) string {
+	return strconv.FormatFloat(version, 'f', 1, 64)
+}
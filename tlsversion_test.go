@@ -0,0 +1,90 @@
+package vital_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/monkescience/vital"
+)
+
+func TestRequireTLSVersion(t *testing.T) {
+	handler := vital.RequireTLSVersion("X-Forwarded-TLS-Version", 1.2)(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	tests := []struct {
+		name           string
+		tlsVersion     uint16
+		header         string
+		expectedStatus int
+	}{
+		{
+			name:           "direct TLS 1.1 is rejected",
+			tlsVersion:     tls.VersionTLS11,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "direct TLS 1.3 is accepted",
+			tlsVersion:     tls.VersionTLS13,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "direct TLS takes precedence over the header",
+			tlsVersion:     tls.VersionTLS12,
+			header:         "TLSv1.0",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "header-reported TLS 1.0 is rejected",
+			header:         "TLSv1.0",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "header-reported TLS 1.2 is accepted",
+			header:         "1.2",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unparseable header is rejected",
+			header:         "SSLv3",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "no TLS information passes through",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a request with direct or proxy-reported TLS metadata
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.tlsVersion != 0 {
+				req.TLS = &tls.ConnectionState{Version: tt.tlsVersion}
+			}
+
+			if tt.header != "" {
+				req.Header.Set("X-Forwarded-TLS-Version", tt.header)
+			}
+
+			rec := httptest.NewRecorder()
+
+			// WHEN: the request is processed
+			handler.ServeHTTP(rec, req)
+
+			// THEN: sub-minimum versions are rejected with a problem response
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+
+			if tt.expectedStatus == http.StatusBadRequest &&
+				rec.Header().Get("Content-Type") != "application/problem+json" {
+				t.Errorf("expected problem response, got %q", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
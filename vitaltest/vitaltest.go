@@ -0,0 +1,138 @@
+// Package vitaltest provides helpers for testing handlers and middleware built with vital.
+package vitaltest
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/monkescience/vital"
+)
+
+// CapturedResponse holds the response written by a handler.
+type CapturedResponse struct {
+	// Status is the HTTP status code.
+	Status int
+	// Header holds the response headers.
+	Header http.Header
+	// Body is the raw response body.
+	Body []byte
+	// Problem is the decoded ProblemDetail if the response is application/problem+json, nil otherwise.
+	Problem *vital.ProblemDetail
+}
+
+// CaptureResponse serves the request with the handler and captures the response.
+func CaptureResponse(handler http.Handler, req *http.Request) CapturedResponse {
+	recorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(recorder, req)
+
+	result := recorder.Result()
+	defer func() { _ = result.Body.Close() }()
+
+	captured := CapturedResponse{
+		Status:  result.StatusCode,
+		Header:  result.Header,
+		Body:    recorder.Body.Bytes(),
+		Problem: nil,
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(result.Header.Get("Content-Type"))
+	if mediaType == "application/problem+json" {
+		captured.Problem = decodeProblem(captured.Body)
+	}
+
+	return captured
+}
+
+// AssertProblem fails the test unless the recorded response is an application/problem+json
+// body that decodes as a ProblemDetail with the given status and title, and its status code
+// and status member agree. It returns the decoded problem for further assertions.
+func AssertProblem(tb testing.TB, rec *httptest.ResponseRecorder, status int, title string) *vital.ProblemDetail {
+	tb.Helper()
+
+	if rec.Code != status {
+		tb.Errorf("expected status %d, got %d", status, rec.Code)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	if mediaType != "application/problem+json" {
+		tb.Fatalf("expected content type application/problem+json, got %q", rec.Header().Get("Content-Type"))
+	}
+
+	problem := decodeProblem(rec.Body.Bytes())
+	if problem == nil {
+		tb.Fatalf("expected a problem body, got %q", rec.Body.String())
+	}
+
+	if problem.Status != status {
+		tb.Errorf("expected problem status %d, got %d", status, problem.Status)
+	}
+
+	if problem.Title != title {
+		tb.Errorf("expected problem title %q, got %q", title, problem.Title)
+	}
+
+	return problem
+}
+
+// decodeProblem decodes a problem body, collecting non-standard members into Extensions.
+// Returns nil if the body is not a valid JSON object.
+func decodeProblem(body []byte) *vital.ProblemDetail {
+	var problem vital.ProblemDetail
+
+	err := json.Unmarshal(body, &problem)
+	if err != nil {
+		return nil
+	}
+
+	var members map[string]any
+
+	err = json.Unmarshal(body, &members)
+	if err != nil {
+		return nil
+	}
+
+	for _, standard := range []string{"type", "title", "status", "detail", "instance"} {
+		delete(members, standard)
+	}
+
+	if len(members) > 0 {
+		problem.Extensions = members
+	}
+
+	return &problem
+}
+
+// StartServer starts the server in the background and returns its listener address as soon
+// as the listener is bound, so tests need no sleeps; use vital.WithPort(0) for a free port.
+// The server is stopped gracefully when the test ends, and the test fails if it could not
+// start or if serving or the shutdown returns an error.
+func StartServer(tb testing.TB, server *vital.Server) net.Addr {
+	tb.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ready, errs := server.StartContext(ctx)
+
+	select {
+	case <-ready:
+	case err := <-errs:
+		cancel()
+		tb.Fatalf("failed to start server: %v", err)
+	}
+
+	tb.Cleanup(func() {
+		cancel()
+
+		for err := range errs {
+			tb.Errorf("server error: %v", err)
+		}
+	})
+
+	return server.ListenerAddr()
+}
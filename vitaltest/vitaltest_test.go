@@ -0,0 +1,143 @@
+package vitaltest_test
+
+import (
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/monkescience/vital"
+	"github.com/monkescience/vital/vitaltest"
+)
+
+func TestCaptureResponse(t *testing.T) {
+	t.Run("captures problem responses", func(t *testing.T) {
+		// GIVEN: a handler that responds with a problem detail
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			vital.RespondProblem(w, vital.NotFound("user not found").
+				WithInstance(r.URL.Path).
+				WithExtension("user_id", "123"))
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/users/123", nil)
+
+		// WHEN: capturing the response
+		captured := vitaltest.CaptureResponse(handler, req)
+
+		// THEN: status, headers, and the decoded problem should be captured
+		if captured.Status != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, captured.Status)
+		}
+
+		if captured.Header.Get("Content-Type") != "application/problem+json" {
+			t.Errorf("expected problem content type, got %q", captured.Header.Get("Content-Type"))
+		}
+
+		if captured.Problem == nil {
+			t.Fatal("expected decoded problem, got nil")
+		}
+
+		if captured.Problem.Status != http.StatusNotFound || captured.Problem.Title != "Not Found" {
+			t.Errorf("unexpected problem: %+v", captured.Problem)
+		}
+
+		if captured.Problem.Detail != "user not found" || captured.Problem.Instance != "/users/123" {
+			t.Errorf("unexpected problem detail or instance: %+v", captured.Problem)
+		}
+
+		if captured.Problem.Extensions["user_id"] != "123" {
+			t.Errorf("expected user_id extension, got %v", captured.Problem.Extensions)
+		}
+	})
+
+	t.Run("captures plain responses through middleware", func(t *testing.T) {
+		// GIVEN: a handler wrapped in middleware that writes plain text
+		handler := vital.RequireBody()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("hello"))
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		// WHEN: capturing the response
+		captured := vitaltest.CaptureResponse(handler, req)
+
+		// THEN: the body should be captured without a problem
+		if captured.Status != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, captured.Status)
+		}
+
+		if string(captured.Body) != "hello" {
+			t.Errorf("expected body %q, got %q", "hello", string(captured.Body))
+		}
+
+		if captured.Problem != nil {
+			t.Errorf("expected no problem, got %+v", captured.Problem)
+		}
+	})
+}
+
+func TestAssertProblem(t *testing.T) {
+	// GIVEN: a recorded problem response
+	rec := httptest.NewRecorder()
+	vital.RespondProblem(rec, vital.Forbidden("no access").WithExtension("reason", "suspended"))
+
+	// WHEN: asserting the problem
+	problem := vitaltest.AssertProblem(t, rec, http.StatusForbidden, "Forbidden")
+
+	// THEN: the decoded problem is returned for further assertions
+	if problem.Detail != "no access" {
+		t.Errorf("expected detail %q, got %q", "no access", problem.Detail)
+	}
+
+	if problem.Extensions["reason"] != "suspended" {
+		t.Errorf("expected reason extension, got %v", problem.Extensions)
+	}
+}
+
+func TestStartServer(t *testing.T) {
+	// GIVEN: a server on a free port
+	server := vital.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+		vital.WithPort(0),
+		vital.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+
+	var addr net.Addr
+
+	t.Run("serves until the test ends", func(t *testing.T) {
+		// WHEN: starting the server with the helper
+		addr = vitaltest.StartServer(t, server)
+
+		// THEN: requests are served without waiting
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://"+addr.String(), nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("expected status %d, got %d", http.StatusNoContent, resp.StatusCode)
+		}
+	})
+
+	// THEN: the server was shut down when the subtest ended
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(t.Context(), "tcp", addr.String())
+	if err == nil {
+		_ = conn.Close()
+
+		t.Error("expected the listener to be closed after the test")
+	}
+}
@@ -0,0 +1,130 @@
+package vital_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/monkescience/vital"
+)
+
+func sign(secret []byte, body string) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write([]byte(body))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyHMAC(t *testing.T) {
+	secret := []byte("webhook-secret")
+	body := `{"event":"push"}`
+
+	var received string
+
+	handler := vital.VerifyHMAC(secret, vital.WithSignatureHeader("X-Hub-Signature-256"))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			received = string(data)
+
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	tests := []struct {
+		name           string
+		signature      string
+		expectedStatus int
+	}{
+		{
+			name:           "valid signature",
+			signature:      sign(secret, body),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "valid signature with sha256 prefix",
+			signature:      "sha256=" + sign(secret, body),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid signature",
+			signature:      "sha256=" + sign([]byte("other-secret"), body),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "malformed signature",
+			signature:      "not-hex",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing header",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a webhook request with the given signature
+			received = ""
+
+			req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+			if tt.signature != "" {
+				req.Header.Set("X-Hub-Signature-256", tt.signature)
+			}
+
+			rec := httptest.NewRecorder()
+
+			// WHEN: the request is processed
+			handler.ServeHTTP(rec, req)
+
+			// THEN: only valid signatures reach the handler, which can re-read the body
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+
+			if tt.expectedStatus == http.StatusOK && received != body {
+				t.Errorf("expected handler to receive %q, got %q", body, received)
+			}
+
+			if tt.expectedStatus == http.StatusUnauthorized {
+				if received != "" {
+					t.Errorf("expected handler not to be called, got body %q", received)
+				}
+
+				if rec.Header().Get("Content-Type") != "application/problem+json" {
+					t.Errorf("expected problem response, got %q", rec.Header().Get("Content-Type"))
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyHMAC_EmptySecretPanics(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret []byte
+	}{
+		{name: "nil secret", secret: nil},
+		{name: "empty secret", secret: []byte{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				// THEN: construction panics with ErrEmptyHMACSecret
+				err, ok := recover().(error)
+				if !ok || !errors.Is(err, vital.ErrEmptyHMACSecret) {
+					t.Errorf("expected panic with ErrEmptyHMACSecret, got %v", err)
+				}
+			}()
+
+			// WHEN: building the middleware without a secret
+			vital.VerifyHMAC(tt.secret)
+		})
+	}
+}